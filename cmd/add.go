@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"siguma0013/reskk-dictionary/internal/dictionary"
+	"siguma0013/reskk-dictionary/internal/utility"
+	dict "siguma0013/reskk-dictionary/pkg/dictionary"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// オプション
+var (
+	addRoot         string
+	addDir          string
+	addReadingTable string
+)
+
+var addCmd = &cobra.Command{
+	Use:          "add <reading> <candidate>...",
+	Short:        "読みと候補を頭文字に対応する辞書ファイルへ追加するコマンド",
+	Args:         cobra.MinimumNArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entry := dict.Entry{Key: args[0], Value: args[1:]}
+
+		if err := dict.Validate(entry); err != nil {
+			return err
+		}
+
+		name, ok := initialFileName(entry.Key)
+		if !ok {
+			return fmt.Errorf("no file allows the initial of %q", entry.Key)
+		}
+
+		path := filepath.Join(addDir, name)
+
+		configs, err := loadDirectoryConfig(directoryConfigPath)
+		if err != nil {
+			return err
+		}
+
+		compare, err := resolveCollation(configs, path)
+		if err != nil {
+			return err
+		}
+
+		var entries []dict.Entry
+		if fileExists(path) {
+			current, err := readEntries(path)
+			if err != nil {
+				return err
+			}
+
+			entries = current
+		}
+
+		added, changed := addEntry(entries, entry, compare)
+		if !changed {
+			fmt.Printf("%s: %s already has %s\n", path, entry.Key, strings.Join(entry.Value, ", "))
+			return nil
+		}
+
+		content, err := formatEntries(added)
+		if err != nil {
+			return err
+		}
+
+		// CI と同じチェックを通らない内容は書き出さない
+		config, _ := lookupDirectoryConfig(configs, path)
+
+		if errs := checkAddedContent(path, content, config, compare); len(errs) > 0 {
+			utility.PrintResults([]utility.FileResult{{Path: path, Errors: errs}})
+			return fmt.Errorf("%s would fail the checks", path)
+		}
+
+		others, err := keyLocations(addRoot, entry.Key, path)
+		if err != nil {
+			return err
+		}
+
+		for _, other := range others {
+			fmt.Fprintf(os.Stderr, "warning: %s also exists in %s\n", entry.Key, other)
+		}
+
+		if err := writeSplitFile(path, added); err != nil {
+			return err
+		}
+
+		fmt.Printf("%s: added %s /%s/\n", path, entry.Key, strings.Join(entry.Value, "/"))
+
+		return nil
+	},
+}
+
+func init() {
+	addCmd.Flags().StringVar(&addRoot, "root", "jsonl", "root directory searched for the same key in other files")
+	addCmd.Flags().StringVar(&addDir, "dir", "jsonl/2_char_jukugo", "directory of the initial files (AllowInitials)")
+	addCmd.Flags().StringVar(&addReadingTable, "readings", "data/kanji_readings.tsv", "kanji reading table")
+	rootCmd.AddCommand(addCmd)
+}
+
+// addEntry は entries に entry を追加する
+// 同じキーのエントリがある時は候補を後ろに加え、無い時は compare の順の位置に挿入する
+// 候補が全て既にある時 changed は false
+func addEntry(entries []dict.Entry, entry dict.Entry, compare func(a string, b string) int) (result []dict.Entry, changed bool) {
+	entries = slices.Clone(entries)
+
+	index := slices.IndexFunc(entries, func(current dict.Entry) bool {
+		return current.Key == entry.Key
+	})
+
+	if index < 0 {
+		return insertEntry(entries, entry, compare), true
+	}
+
+	values := dict.Union(entries[index].Value, entry.Value)
+	if len(values) == len(entries[index].Value) {
+		return entries, false
+	}
+
+	entries[index].Value = values
+
+	return entries, true
+}
+
+// checkAddedContent は path に書き出す content に CI と同じチェック (format, initial, sort, reading) を行う
+// config と compare は path のディレクトリ設定と照合順序
+func checkAddedContent(path string, content []byte, config directoryConfig, compare func(a string, b string) int) []error {
+	table, err := loadReadingTable(addReadingTable)
+	if err != nil {
+		return []error{err}
+	}
+
+	return slices.Concat(
+		checkFormatWith(bytes.NewReader(content), config, false),
+		checkInitial(bytes.NewReader(content), dictionary.AllowInitials[filepath.Base(path)]),
+		checkSortedBy(bytes.NewReader(content), compare),
+		checkReading(bytes.NewReader(content), table, false),
+	)
+}
+
+// keyLocations は root 配下で key を持つ path 以外の辞書ファイルを返す
+// 削除リストは対象外
+func keyLocations(root string, key string, path string) ([]string, error) {
+	paths, err := resolveDictionaryFiles(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", root, err)
+	}
+
+	var locations []string
+
+	for _, other := range paths {
+		if filepath.Clean(other) == filepath.Clean(path) || dict.IsDenyFile(other) {
+			continue
+		}
+
+		entries, err := readEntries(other)
+		if err != nil {
+			return nil, err
+		}
+
+		if slices.ContainsFunc(entries, func(entry dict.Entry) bool { return entry.Key == key }) {
+			locations = append(locations, other)
+		}
+	}
+
+	return locations, nil
+}
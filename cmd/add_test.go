@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	dict "siguma0013/reskk-dictionary/pkg/dictionary"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestAddEntry(t *testing.T) {
+	entries := []dict.Entry{
+		{Key: "あんごう", Value: []string{"暗号"}},
+		{Key: "いちらん", Value: []string{"一覧"}},
+	}
+
+	// 新しいキーは五十音順の位置に入る
+	got, changed := addEntry(entries, dict.Entry{Key: "あいさつ", Value: []string{"挨拶"}}, dict.Compare)
+	if !changed || !slices.Equal(entryKeys(got), []string{"あいさつ", "あんごう", "いちらん"}) {
+		t.Errorf("addEntry(new key) = %v, %v", entryKeys(got), changed)
+	}
+
+	// 既存のキーには候補を後ろに加える
+	got, changed = addEntry(entries, dict.Entry{Key: "あんごう", Value: []string{"暗号", "安号"}}, dict.Compare)
+	if !changed || !slices.Equal(got[0].Value, []string{"暗号", "安号"}) {
+		t.Errorf("addEntry(existing key) = %v, %v", got[0].Value, changed)
+	}
+
+	// 元のスライスは変更しない
+	if !slices.Equal(entries[0].Value, []string{"暗号"}) {
+		t.Errorf("addEntry modified the input: %v", entries[0].Value)
+	}
+
+	// 候補が全てある時は変更なし
+	if _, changed := addEntry(entries, dict.Entry{Key: "いちらん", Value: []string{"一覧"}}, dict.Compare); changed {
+		t.Errorf("addEntry(existing candidate) should not change entries")
+	}
+}
+
+func TestAddCommand(t *testing.T) {
+	defer func(root string, dir string, readings string) {
+		addRoot, addDir, addReadingTable = root, dir, readings
+	}(addRoot, addDir, addReadingTable)
+
+	dir := t.TempDir()
+	addRoot = filepath.Join(dir, "jsonl")
+	addDir = filepath.Join(dir, "jsonl", "2_char_jukugo")
+	addReadingTable = filepath.Join(dir, "readings.tsv")
+
+	if err := os.MkdirAll(addDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	writeTestFile(t, addReadingTable, "暗\tあん\n号\tごう\n挨\tあい\n拶\tさつ\n一\tいち\n")
+	writeTestFile(t, filepath.Join(addDir, "01-a.jsonl"), `{"key": "あんごう", "value": ["暗号"]}`+"\n")
+	writeTestFile(t, filepath.Join(addRoot, "number.jsonl"), `{"key": "いち", "value": ["一"]}`+"\n")
+
+	if err := addCmd.RunE(addCmd, []string{"あいさつ", "挨拶"}); err != nil {
+		t.Fatalf("add command failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(addDir, "01-a.jsonl"))
+	if err != nil {
+		t.Fatalf("read 01-a.jsonl: %v", err)
+	}
+
+	want := strings.Join([]string{
+		`{"key": "あいさつ", "value": ["挨拶"]}`,
+		`{"key": "あんごう", "value": ["暗号"]}`,
+	}, "\n") + "\n"
+
+	if string(content) != want {
+		t.Errorf("01-a.jsonl = %q, want %q", content, want)
+	}
+
+	// チェックを通らない候補は書き出さない
+	if err := addCmd.RunE(addCmd, []string{"あんごう", "暗"}); err == nil {
+		t.Errorf("expected an error for a candidate failing the checks")
+	}
+
+	// 存在しないファイルは新しく作る
+	if err := addCmd.RunE(addCmd, []string{"まくら", "枕"}); err != nil {
+		t.Fatalf("add command failed: %v", err)
+	}
+
+	if !fileExists(filepath.Join(addDir, "07-ma.jsonl")) {
+		t.Errorf("07-ma.jsonl was not created")
+	}
+
+	// 頭文字に対応するファイルが無い読み
+	if err := addCmd.RunE(addCmd, []string{"ヴぁ", "字"}); err == nil {
+		t.Errorf("expected an error for an unknown initial")
+	}
+}
+
+func TestAddCommand_Collation(t *testing.T) {
+	defer func(root string, dir string, readings string, config string) {
+		addRoot, addDir, addReadingTable, directoryConfigPath = root, dir, readings, config
+	}(addRoot, addDir, addReadingTable, directoryConfigPath)
+
+	dir := t.TempDir()
+	addRoot = filepath.Join(dir, "jsonl")
+	addDir = filepath.Join(addRoot, "2_char_jukugo")
+	addReadingTable = filepath.Join(dir, "readings.tsv")
+	directoryConfigPath = filepath.Join(dir, "directory_config.yml")
+
+	if err := os.MkdirAll(addDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	// codepoint では っ (U+3063) が つ (U+3064) より前に並ぶ
+	writeTestFile(t, directoryConfigPath, "directories:\n  "+strconv.Quote(addDir)+":\n    collation: \"codepoint\"\n")
+	writeTestFile(t, addReadingTable, "包\tつつ\n突\tつっ\n込\tこみ\n")
+	writeTestFile(t, filepath.Join(addDir, "04-ta.jsonl"), `{"key": "つつみ", "value": ["包み"]}`+"\n")
+
+	if err := addCmd.RunE(addCmd, []string{"つっこみ", "突込"}); err != nil {
+		t.Fatalf("add command failed: %v", err)
+	}
+
+	entries, err := readEntries(filepath.Join(addDir, "04-ta.jsonl"))
+	if err != nil {
+		t.Fatalf("read 04-ta.jsonl: %v", err)
+	}
+
+	if keys := entryKeys(entries); !slices.Equal(keys, []string{"つっこみ", "つつみ"}) {
+		t.Errorf("04-ta.jsonl keys = %v", keys)
+	}
+}
+
+func TestKeyLocations(t *testing.T) {
+	dir := t.TempDir()
+
+	writeTestFile(t, filepath.Join(dir, "a.jsonl"), `{"key": "いち", "value": ["一"]}`+"\n")
+	writeTestFile(t, filepath.Join(dir, "b.jsonl"), `{"key": "いち", "value": ["壱"]}`+"\n")
+	writeTestFile(t, filepath.Join(dir, "c.deny.jsonl"), `{"key": "いち"}`+"\n")
+
+	got, err := keyLocations(dir, "いち", filepath.Join(dir, "a.jsonl"))
+	if err != nil {
+		t.Fatalf("keyLocations: %v", err)
+	}
+
+	if !slices.Equal(got, []string{filepath.Join(dir, "b.jsonl")}) {
+		t.Errorf("keyLocations = %v", got)
+	}
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	dict "siguma0013/reskk-dictionary/pkg/dictionary"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// unspecifiedLicense は出典の分からない候補のライセンス表記
+const unspecifiedLicense = "(unspecified)"
+
+// loadAttribution は merge_order.yml の attribution からファイル単位の出典を読み込む
+// キーは files と同じく Glob のパターン
+func loadAttribution(path string) (map[string]dict.Provenance, error) {
+	orderFile, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read order file")
+	}
+
+	var order struct {
+		Attribution map[string]dict.Provenance `yaml:"attribution"`
+	}
+
+	if err := yaml.Unmarshal(orderFile, &order); err != nil {
+		return nil, fmt.Errorf("failed to read order file")
+	}
+
+	return order.Attribution, nil
+}
+
+// fileProvenance は path に一致するパターンのうち最も長いパターンの出典を返す
+func fileProvenance(attribution map[string]dict.Provenance, path string) dict.Provenance {
+	var found dict.Provenance
+	matchedLength := -1
+
+	for pattern, provenance := range attribution {
+		if ok, _ := filepath.Match(pattern, path); !ok {
+			continue
+		}
+
+		if len(pattern) > matchedLength {
+			found = provenance
+			matchedLength = len(pattern)
+		}
+	}
+
+	return found
+}
+
+// makeAttribution は候補ごとに最初に追加した入力の出典を集計する
+// Merge と同じく先に現れた候補を優先し、エントリの出典はファイル単位の出典より優先する
+// 削除リストで取り除かれる候補は数えない
+// user は Overlay で先頭に重ねる利用者の辞書で、マージ結果と同じく絞り込みも削除もせず最優先で数える
+func makeAttribution(user []dict.Entry, paths []string, attribution map[string]dict.Provenance, policy dict.MergePolicy) (map[dict.Provenance]int, error) {
+	counts := make(map[dict.Provenance]int)
+	seen := make(map[[2]string]bool)
+
+	for _, entry := range user {
+		for _, value := range entry.Value {
+			candidate := [2]string{entry.Key, value}
+
+			if !seen[candidate] {
+				seen[candidate] = true
+				counts[entry.Provenance]++
+			}
+		}
+	}
+
+	deny, err := dict.LoadDenyList(dict.FileSources(paths), maxLineSize)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range paths {
+		if dict.IsDenyFile(path) {
+			continue
+		}
+
+		defaults := fileProvenance(attribution, path)
+
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+
+		reader := dict.NewReader(file)
+		reader.SetMaxLineSize(maxLineSize)
+
+		for {
+			entry, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			if err != nil {
+				file.Close()
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+
+			if !policy.Accepts(entry) {
+				continue
+			}
+
+			provenance := defaults.Overlay(entry.Provenance)
+
+			for _, value := range entry.Value {
+				candidate := [2]string{entry.Key, value}
+
+				if seen[candidate] || deny.Denies(entry.Key, value) {
+					continue
+				}
+
+				seen[candidate] = true
+				counts[provenance]++
+			}
+		}
+
+		file.Close()
+	}
+
+	return counts, nil
+}
+
+// writeAttribution は出典ごとの候補数を Markdown で出力する
+func writeAttribution(writer io.Writer, counts map[dict.Provenance]int) {
+	fmt.Fprintln(writer, "## Attribution")
+	fmt.Fprintln(writer)
+	fmt.Fprintln(writer, "| License | Source | Contributor | Candidates |")
+	fmt.Fprintln(writer, "| --- | --- | --- | ---: |")
+
+	rows := slices.SortedFunc(maps.Keys(counts), func(a dict.Provenance, b dict.Provenance) int {
+		if a.License != b.License {
+			return compareLicense(a.License, b.License)
+		}
+
+		if a.Source != b.Source {
+			return strings.Compare(a.Source, b.Source)
+		}
+
+		return strings.Compare(a.Contributor, b.Contributor)
+	})
+
+	total := 0
+
+	for _, row := range rows {
+		license := row.License
+		if license == "" {
+			license = unspecifiedLicense
+		}
+
+		fmt.Fprintf(writer, "| %s | %s | %s | %d |\n", license, row.Source, row.Contributor, counts[row])
+		total += counts[row]
+	}
+
+	fmt.Fprintf(writer, "| **Total** | | | %d |\n", total)
+}
+
+// compareLicense はライセンスを名前順に並べ、指定の無いものを最後にする
+func compareLicense(a string, b string) int {
+	switch {
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	return strings.Compare(a, b)
+}
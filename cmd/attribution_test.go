@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	dict "siguma0013/reskk-dictionary/pkg/dictionary"
+	"strings"
+	"testing"
+)
+
+func TestMakeAttribution(t *testing.T) {
+	d := t.TempDir()
+
+	local := filepath.Join(d, "local.jsonl")
+	imported := filepath.Join(d, "imported.jsonl")
+
+	if err := os.WriteFile(local, []byte(strings.Join([]string{
+		`{"key": "あい", "value": ["愛"]}`,
+		`{"key": "きのう", "value": ["機能"], "contributor": "alice", "license": "CC0-1.0"}`,
+	}, "\n")+"\n"), 0o644); err != nil {
+		t.Fatalf("write local.jsonl: %v", err)
+	}
+
+	if err := os.WriteFile(imported, []byte(strings.Join([]string{
+		`{"key": "あい", "value": ["愛", "藍"]}`,
+		`{"key": "いにしえ", "value": ["古"], "tags": ["archaic"]}`,
+	}, "\n")+"\n"), 0o644); err != nil {
+		t.Fatalf("write imported.jsonl: %v", err)
+	}
+
+	skk := dict.Provenance{Source: "SKK-JISYO.L", License: "GPL-2.0-or-later"}
+	attribution := map[string]dict.Provenance{
+		filepath.Join(d, "*.jsonl"): {License: "MIT"},
+		imported:                    skk,
+	}
+
+	counts, err := makeAttribution(nil, []string{local, imported}, attribution, dict.MergePolicy{ExcludeTags: []string{"archaic"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 愛 は先に現れた local.jsonl、藍 だけが imported.jsonl の出典になる
+	expected := map[dict.Provenance]int{
+		{License: "MIT"}: 1,
+		{Contributor: "alice", License: "CC0-1.0"}: 1,
+		skk: 1,
+	}
+
+	if len(counts) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, counts)
+	}
+
+	for provenance, count := range expected {
+		if counts[provenance] != count {
+			t.Fatalf("expected %v, got %v", expected, counts)
+		}
+	}
+
+	var buffer bytes.Buffer
+	writeAttribution(&buffer, counts)
+
+	for _, row := range []string{
+		"| CC0-1.0 |  | alice | 1 |",
+		"| GPL-2.0-or-later | SKK-JISYO.L |  | 1 |",
+		"| **Total** | | | 3 |",
+	} {
+		if !strings.Contains(buffer.String(), row) {
+			t.Fatalf("expected %q in report, got:\n%s", row, buffer.String())
+		}
+	}
+}
+
+func TestMakeAttribution_User(t *testing.T) {
+	d := t.TempDir()
+
+	local := filepath.Join(d, "local.jsonl")
+	writeTestFile(t, local, `{"key": "あい", "value": ["愛", "藍"]}`+"\n")
+
+	user := []dict.Entry{
+		{Key: "あい", Value: []string{"藍"}, Provenance: dict.Provenance{Contributor: "bob"}},
+		{Key: "かな", Value: []string{"仮名"}, Provenance: dict.Provenance{Contributor: "bob"}},
+	}
+
+	counts, err := makeAttribution(user, []string{local}, nil, dict.MergePolicy{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 利用者の辞書の候補は先頭に重ねるため最優先で数える
+	if counts[dict.Provenance{Contributor: "bob"}] != 2 || counts[dict.Provenance{}] != 1 {
+		t.Fatalf("unexpected counts: %v", counts)
+	}
+}
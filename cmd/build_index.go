@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	dict "siguma0013/reskk-dictionary/pkg/dictionary"
+	"siguma0013/reskk-dictionary/pkg/reskkdict"
+
+	"github.com/spf13/cobra"
+)
+
+// オプション
+var (
+	buildIndexOrderPath  string
+	buildIndexOutputPath string
+)
+
+var buildIndexCmd = &cobra.Command{
+	Use:   "build-index",
+	Short: "マージした辞書から検索用のバイナリインデックスを作成するコマンド",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		orders, err := makeMergeOrder(buildIndexOrderPath)
+		if err != nil {
+			return fmt.Errorf("nothing order %s: %w", buildIndexOrderPath, err)
+		}
+
+		var entries []reskkdict.Entry
+
+		for entry, err := range dict.Merge(dict.FileSources(orders), mergePolicy(dict.Compare)) {
+			if err != nil {
+				return fmt.Errorf("missing merge data: %w", err)
+			}
+
+			// キーの無いレコードは検索できないため除外
+			if entry.Key == "" {
+				continue
+			}
+
+			entries = append(entries, reskkdict.Entry{Key: entry.Key, Candidates: entry.Value})
+		}
+
+		outFile, err := os.Create(buildIndexOutputPath)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", buildIndexOutputPath, err)
+		}
+
+		defer outFile.Close()
+
+		if err := reskkdict.Write(outFile, entries); err != nil {
+			return fmt.Errorf("failed to write index: %w", err)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	buildIndexCmd.Flags().StringVar(&buildIndexOrderPath, "input", "merge_order.yml", "input order file")
+	buildIndexCmd.Flags().StringVar(&buildIndexOutputPath, "output", "reskk-dictionary.idx", "output index file")
+	rootCmd.AddCommand(buildIndexCmd)
+}
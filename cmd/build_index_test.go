@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"os"
+	"siguma0013/reskk-dictionary/pkg/reskkdict"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBuildIndexCommand(t *testing.T) {
+	d := t.TempDir()
+	oldwd, _ := os.Getwd()
+	defer os.Chdir(oldwd)
+	if err := os.Chdir(d); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+
+	if err := os.WriteFile("a.jsonl", []byte(strings.Join([]string{
+		`{"key":"きのう","value":["機能"]}`,
+		`{"value":["no_key"]}`,
+	}, "\n")+"\n"), 0o644); err != nil {
+		t.Fatalf("write a.jsonl: %v", err)
+	}
+
+	if err := os.WriteFile("b.jsonl", []byte(`{"key":"きのう","value":["昨日"]}`+"\n"), 0o644); err != nil {
+		t.Fatalf("write b.jsonl: %v", err)
+	}
+
+	if err := os.WriteFile("merge_order.yml", []byte("files:\n  - \"a.jsonl\"\n  - \"b.jsonl\"\n"), 0o644); err != nil {
+		t.Fatalf("write merge_order.yml: %v", err)
+	}
+
+	if err := buildIndexCmd.RunE(nil, nil); err != nil {
+		t.Fatalf("build-index command failed: %v", err)
+	}
+
+	index, err := reskkdict.Open("reskk-dictionary.idx")
+	if err != nil {
+		t.Fatalf("open index: %v", err)
+	}
+
+	defer index.Close()
+
+	if index.Len() != 1 {
+		t.Fatalf("expected unkeyed record to be skipped, got %d entries", index.Len())
+	}
+
+	if candidates, _ := index.Lookup("きのう"); !slices.Equal(candidates, []string{"機能", "昨日"}) {
+		t.Fatalf("expected merged candidates, got %v", candidates)
+	}
+}
@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	dict "siguma0013/reskk-dictionary/pkg/dictionary"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// unknownSource はキーの定義元ファイルが分からない時の表示名
+const unknownSource = "(unknown)"
+
+// オプション
+var (
+	changelogOutputPath string
+	changelogTitle      string
+)
+
+var changelogCmd = &cobra.Command{
+	Use:          "changelog <old> [new]",
+	Short:        "前回リリースとの差分から Markdown の変更履歴を作成するコマンド",
+	Long:         "old と new (既定は merge_order.yml) をエントリ単位で比較し、ファイルごとの件数と追加された読みを Markdown で出力する",
+	Args:         cobra.RangeArgs(1, 2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		newPath := "merge_order.yml"
+		if len(args) == 2 {
+			newPath = args[1]
+		}
+
+		beforeFiles, err := resolveDictionaryFiles(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", args[0], err)
+		}
+
+		afterFiles, err := resolveDictionaryFiles(newPath)
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", newPath, err)
+		}
+
+		before, err := makeMergeData(beforeFiles)
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", args[0], err)
+		}
+
+		after, err := makeMergeData(afterFiles)
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", newPath, err)
+		}
+
+		// 定義元は新しい辞書を優先し、削除されたキーは古い辞書から探す
+		sources, err := keySources(afterFiles)
+		if err != nil {
+			return err
+		}
+
+		beforeSources, err := keySources(beforeFiles)
+		if err != nil {
+			return err
+		}
+
+		for key, path := range beforeSources {
+			if _, ok := sources[key]; !ok {
+				sources[key] = path
+			}
+		}
+
+		writer := io.Writer(os.Stdout)
+
+		if changelogOutputPath != "" {
+			outFile, err := os.Create(changelogOutputPath)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", changelogOutputPath, err)
+			}
+
+			defer outFile.Close()
+
+			writer = outFile
+		}
+
+		writeChangelog(writer, changelogTitle, dict.Diff(before, after), sources)
+
+		return nil
+	},
+}
+
+func init() {
+	changelogCmd.Flags().StringVar(&changelogOutputPath, "output", "", "output file (default: stdout)")
+	changelogCmd.Flags().StringVar(&changelogTitle, "title", "Changes", "heading of the changelog")
+	rootCmd.AddCommand(changelogCmd)
+}
+
+// keySources はキーごとに最初に定義されているファイルを返す
+// マージ済みファイルのように1ファイルだけの時は定義元が分からないため空を返す
+func keySources(paths []string) (map[string]string, error) {
+	sources := make(map[string]string)
+
+	if len(paths) <= 1 {
+		return sources, nil
+	}
+
+	for _, path := range paths {
+		// 削除リストはキーの定義元にならない
+		if dict.IsDenyFile(path) {
+			continue
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+
+		reader := dict.NewReader(file)
+		reader.SetMaxLineSize(maxLineSize)
+
+		for {
+			entry, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			if err != nil {
+				file.Close()
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+
+			if _, ok := sources[entry.Key]; !ok {
+				sources[entry.Key] = path
+			}
+		}
+
+		file.Close()
+	}
+
+	return sources, nil
+}
+
+// changelogCount はファイルごとの変更件数
+type changelogCount struct {
+	added   int
+	removed int
+	changed int
+}
+
+// writeChangelog は差分を Markdown で出力する
+func writeChangelog(writer io.Writer, title string, result dict.DiffResult, sources map[string]string) {
+	counts := make(map[string]*changelogCount)
+
+	count := func(key string) *changelogCount {
+		source, ok := sources[key]
+		if !ok {
+			source = unknownSource
+		}
+
+		if _, ok := counts[source]; !ok {
+			counts[source] = &changelogCount{}
+		}
+
+		return counts[source]
+	}
+
+	for _, entry := range result.Added {
+		count(entry.Key).added++
+	}
+
+	for _, entry := range result.Removed {
+		count(entry.Key).removed++
+	}
+
+	for _, change := range result.Changed {
+		count(change.Key).changed++
+	}
+
+	fmt.Fprintf(writer, "## %s\n\n", title)
+
+	if result.Empty() {
+		fmt.Fprintln(writer, "No dictionary changes.")
+		return
+	}
+
+	fmt.Fprintln(writer, "| File | Added | Removed | Changed |")
+	fmt.Fprintln(writer, "| --- | ---: | ---: | ---: |")
+
+	files := make([]string, 0, len(counts))
+	for file := range counts {
+		files = append(files, file)
+	}
+
+	slices.Sort(files)
+
+	for _, file := range files {
+		c := counts[file]
+		fmt.Fprintf(writer, "| %s | %d | %d | %d |\n", file, c.added, c.removed, c.changed)
+	}
+
+	fmt.Fprintf(writer, "| **Total** | %d | %d | %d |\n", len(result.Added), len(result.Removed), len(result.Changed))
+
+	writeChangelogEntries(writer, "Added readings", result.Added)
+	writeChangelogEntries(writer, "Removed readings", result.Removed)
+
+	if len(result.Changed) > 0 {
+		fmt.Fprintf(writer, "\n### Changed readings\n\n")
+
+		for _, change := range result.Changed {
+			var parts []string
+
+			for _, value := range change.Added {
+				parts = append(parts, "+"+value)
+			}
+
+			for _, value := range change.Removed {
+				parts = append(parts, "-"+value)
+			}
+
+			if change.Reordered {
+				parts = append(parts, "reordered")
+			}
+
+			fmt.Fprintf(writer, "- %s: %s\n", change.Key, strings.Join(parts, " "))
+		}
+	}
+}
+
+// writeChangelogEntries はエントリの一覧を Markdown のリストで出力する
+func writeChangelogEntries(writer io.Writer, heading string, entries []dict.Entry) {
+	if len(entries) == 0 {
+		return
+	}
+
+	fmt.Fprintf(writer, "\n### %s\n\n", heading)
+
+	for _, entry := range entries {
+		fmt.Fprintf(writer, "- %s: %s\n", entry.Key, strings.Join(entry.Value, " / "))
+	}
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	dict "siguma0013/reskk-dictionary/pkg/dictionary"
+	"strings"
+	"testing"
+)
+
+func TestWriteChangelog(t *testing.T) {
+	before := []dict.Entry{
+		{Key: "あい", Value: []string{"愛"}},
+		{Key: "かい", Value: []string{"回"}},
+	}
+
+	after := []dict.Entry{
+		{Key: "かい", Value: []string{"回", "貝"}},
+		{Key: "きのう", Value: []string{"機能", "昨日"}},
+		{Key: "さくじょ", Value: []string{"削除"}},
+	}
+
+	sources := map[string]string{
+		"かい":   "jsonl/number_word.jsonl",
+		"きのう":  "jsonl/2_char_jukugo/02-ka.jsonl",
+		"さくじょ": "jsonl/2_char_jukugo/03-sa.jsonl",
+	}
+
+	var output bytes.Buffer
+	writeChangelog(&output, "v1.0.0", dict.Diff(before, after), sources)
+
+	expected := strings.Join([]string{
+		"## v1.0.0",
+		"",
+		"| File | Added | Removed | Changed |",
+		"| --- | ---: | ---: | ---: |",
+		"| (unknown) | 0 | 1 | 0 |",
+		"| jsonl/2_char_jukugo/02-ka.jsonl | 1 | 0 | 0 |",
+		"| jsonl/2_char_jukugo/03-sa.jsonl | 1 | 0 | 0 |",
+		"| jsonl/number_word.jsonl | 0 | 0 | 1 |",
+		"| **Total** | 2 | 1 | 1 |",
+		"",
+		"### Added readings",
+		"",
+		"- きのう: 機能 / 昨日",
+		"- さくじょ: 削除",
+		"",
+		"### Removed readings",
+		"",
+		"- あい: 愛",
+		"",
+		"### Changed readings",
+		"",
+		"- かい: +貝",
+		"",
+	}, "\n")
+
+	if output.String() != expected {
+		t.Fatalf("unexpected changelog:\n%s", output.String())
+	}
+}
+
+func TestWriteChangelog_Empty(t *testing.T) {
+	var output bytes.Buffer
+	writeChangelog(&output, "Changes", dict.DiffResult{}, nil)
+
+	if output.String() != "## Changes\n\nNo dictionary changes.\n" {
+		t.Fatalf("unexpected changelog: %q", output.String())
+	}
+}
+
+func TestKeySources_DenyFile(t *testing.T) {
+	d := t.TempDir()
+
+	paths := []string{filepath.Join(d, "a.deny.jsonl"), filepath.Join(d, "b.jsonl")}
+
+	writeTestFile(t, paths[0], `{"key": "あい", "value": ["藍"]}`+"\n")
+	writeTestFile(t, paths[1], `{"key": "あい", "value": ["愛", "藍"]}`+"\n")
+
+	sources, err := keySources(paths)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if sources["あい"] != paths[1] {
+		t.Fatalf("expected the key to come from %s, got %q", paths[1], sources["あい"])
+	}
+}
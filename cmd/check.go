@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"os"
+	"siguma0013/reskk-dictionary/internal/utility"
+	dict "siguma0013/reskk-dictionary/pkg/dictionary"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// オプション
+var (
+	isCheckGenerated bool
+)
+
+var checkCmd = &cobra.Command{
+	Use:          "check",
+	Short:        "生成ファイルが生成元と一致するかチェックするコマンド",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !isCheckGenerated {
+			return fmt.Errorf("no check selected (available: --generated)")
+		}
+
+		manifest, err := loadGeneratedManifest(generatedManifestPath)
+		if err != nil {
+			return err
+		}
+
+		if utility.PrintResults(checkGenerated(manifest)) {
+			return fmt.Errorf("stale generated files found")
+		}
+
+		fmt.Println("All generated files are up to date")
+
+		return nil
+	},
+}
+
+func init() {
+	checkCmd.Flags().BoolVar(&isCheckGenerated, "generated", false, "check that regenerating the generated files changes nothing")
+	checkCmd.Flags().StringVar(&generatedManifestPath, "manifest", "generated.yml", "manifest of generated files")
+	rootCmd.AddCommand(checkCmd)
+}
+
+// checkGenerated は生成ファイル一覧の全ファイルを再生成し、内容が変わらないかチェックする
+func checkGenerated(manifest generatedManifest) []utility.FileResult {
+	var results []utility.FileResult
+
+	for _, path := range slices.Sorted(maps.Keys(manifest.Files)) {
+		results = append(results, utility.FileResult{Path: path, Errors: checkGeneratedFile(path, manifest.Files[path])})
+	}
+
+	return results
+}
+
+// checkGeneratedFile は生成ファイル1つ分の手編集と生成元の変更をチェックする
+func checkGeneratedFile(path string, record generatedFile) []error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return []error{err}
+	}
+
+	var results []error
+
+	if contentHash(content) != record.Hash {
+		results = append(results, fmt.Errorf("content differs from the recorded hash (edited by hand?)"))
+	}
+
+	command, ok := findGenerator(record.Generator)
+	if !ok {
+		return append(results, fmt.Errorf("unknown generator %q", record.Generator))
+	}
+
+	entries, err := regenerate(command, record.Options)
+	if err != nil {
+		return append(results, err)
+	}
+
+	regenerated, err := formatGenerated(entries)
+	if err != nil {
+		return append(results, err)
+	}
+
+	if !bytes.Equal(content, regenerated) {
+		results = append(results, fmt.Errorf("regenerating changes the file%s; run: reskk-dictionary generate %s %s--output %s", changedInputs(record.Inputs), record.Generator, generatorOptions(record.Options), path))
+	}
+
+	return results
+}
+
+// regenerate は記録されたオプション options で生成コマンド command の生成処理を実行する
+// 生成処理はオプションをパッケージ変数から参照するため、終了後にオプションを元の値へ戻す
+func regenerate(command *cobra.Command, options map[string]string) ([]dict.Entry, error) {
+	type flagState struct {
+		value   string
+		changed bool
+	}
+
+	states := make(map[*pflag.Flag]flagState)
+
+	command.Flags().VisitAll(func(flag *pflag.Flag) {
+		states[flag] = flagState{value: flag.Value.String(), changed: flag.Changed}
+	})
+
+	defer func() {
+		for flag, state := range states {
+			flag.Value.Set(state.value)
+			flag.Changed = state.changed
+		}
+	}()
+
+	for _, name := range slices.Sorted(maps.Keys(options)) {
+		if err := command.Flags().Set(name, options[name]); err != nil {
+			return nil, fmt.Errorf("invalid option --%s: %w", name, err)
+		}
+	}
+
+	entries, _, err := generators[command.Name()]()
+
+	return entries, err
+}
+
+// changedInputs は生成時から変更された入力ファイルを説明する文字列を返す
+// 入力の変更だけでは生成結果が変わらないこともあるため、エラーの補足として利用する
+func changedInputs(inputs map[string]string) string {
+	var changed []string
+
+	for _, input := range slices.Sorted(maps.Keys(inputs)) {
+		content, err := os.ReadFile(input)
+		if err != nil || contentHash(content) != inputs[input] {
+			changed = append(changed, input)
+		}
+	}
+
+	if len(changed) == 0 {
+		return ""
+	}
+
+	return " (changed inputs: " + strings.Join(changed, ", ") + ")"
+}
+
+// generatorOptions は記録されたオプションをコマンドライン引数の形式にする
+func generatorOptions(options map[string]string) string {
+	var builder strings.Builder
+
+	for _, name := range slices.Sorted(maps.Keys(options)) {
+		fmt.Fprintf(&builder, "--%s %s ", name, options[name])
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testNumbers は生成コマンドの入力に使う数字と桁の読み
+var testNumbers = strings.Join([]string{
+	`{"key": "いち", "value": ["一", "壱"]}`,
+	`{"key": "に", "value": ["二", "弐"]}`,
+	`{"key": "さん", "value": ["三", "参"]}`,
+	`{"key": "よん", "value": ["四", "肆"]}`,
+	`{"key": "ご", "value": ["五", "伍"]}`,
+	`{"key": "ろく", "value": ["六", "陸"]}`,
+	`{"key": "なな", "value": ["七", "漆"]}`,
+	`{"key": "はち", "value": ["八", "捌"]}`,
+	`{"key": "きゅう", "value": ["九", "玖"]}`,
+	`{"key": "じゅう", "value": ["十", "拾"]}`,
+	`{"key": "ひゃく", "value": ["百", "佰"]}`,
+	`{"key": "せん", "value": ["千", "阡"]}`,
+	`{"key": "まん", "value": ["万", "萬"]}`,
+}, "\n") + "\n"
+
+func TestCheckGenerated(t *testing.T) {
+	dir := t.TempDir()
+
+	numbersPath := filepath.Join(dir, "number.jsonl")
+	countersPath := filepath.Join(dir, "number_word.jsonl")
+	outputPath := filepath.Join(dir, "number_counter.jsonl")
+
+	writeTestFile(t, numbersPath, testNumbers)
+	writeTestFile(t, countersPath, `{"key": "かい", "value": ["回"]}`+"\n")
+
+	defer func(numbers string, counters string, manifest string) {
+		generateCountersNumbers, generateCountersInput, generatedManifestPath = numbers, counters, manifest
+	}(generateCountersNumbers, generateCountersInput, generatedManifestPath)
+
+	generateCountersNumbers = numbersPath
+	generateCountersInput = countersPath
+	generatedManifestPath = filepath.Join(dir, "generated.yml")
+
+	if err := runGenerator(generateCountersCmd, outputPath); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	manifest, err := loadGeneratedManifest(generatedManifestPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if results := checkGenerated(manifest); len(results) != 1 || len(results[0].Errors) != 0 {
+		t.Fatalf("expected fresh generated file, got %v", results)
+	}
+
+	// 手編集は検出される
+	content, _ := os.ReadFile(outputPath)
+	writeTestFile(t, outputPath, string(content)+`{"key": "ぜろかい", "value": ["零回"]}`+"\n")
+
+	if results := checkGenerated(manifest); len(results[0].Errors) != 2 {
+		t.Fatalf("expected hash and regeneration errors, got %v", results[0].Errors)
+	}
+
+	// 入力の変更も検出される
+	writeTestFile(t, outputPath, string(content))
+	writeTestFile(t, countersPath, `{"key": "かい", "value": ["回", "階"]}`+"\n")
+
+	results := checkGenerated(manifest)
+	if len(results[0].Errors) != 1 || !strings.Contains(results[0].Errors[0].Error(), "changed inputs: "+countersPath) {
+		t.Fatalf("expected regeneration error with changed input, got %v", results[0].Errors)
+	}
+}
+
+func TestCheckGenerated_Options(t *testing.T) {
+	dir := t.TempDir()
+
+	numbersPath := filepath.Join(dir, "number.jsonl")
+	smallPath := filepath.Join(dir, "small.jsonl")
+	largePath := filepath.Join(dir, "large.jsonl")
+
+	writeTestFile(t, numbersPath, testNumbers)
+
+	defer func(input string, min int, max int, manifest string) {
+		generateNumbersInput, generateNumbersMin, generateNumbersMax, generatedManifestPath = input, min, max, manifest
+	}(generateNumbersInput, generateNumbersMin, generateNumbersMax, generatedManifestPath)
+
+	generateNumbersInput = numbersPath
+	generatedManifestPath = filepath.Join(dir, "generated.yml")
+
+	// 同じ生成コマンドを異なるオプションで2回生成する
+	generateNumbersMin, generateNumbersMax = 1, 3
+	if err := runGenerator(generateNumbersCmd, smallPath); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	generateNumbersMin, generateNumbersMax = 1, 120
+	if err := runGenerator(generateNumbersCmd, largePath); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	manifest, err := loadGeneratedManifest(generatedManifestPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 記録ごとのオプションで再生成し、前の記録のオプションを引き継がない
+	delete(manifest.Files[filepath.ToSlash(smallPath)].Options, "max")
+	generateNumbersMin, generateNumbersMax = 1, 3
+
+	for _, result := range checkGenerated(manifest) {
+		if len(result.Errors) != 0 {
+			t.Errorf("%s: expected fresh generated file, got %v", result.Path, result.Errors)
+		}
+	}
+
+	// チェック後のオプションは元の値に戻る
+	if generateNumbersInput != numbersPath || generateNumbersMin != 1 || generateNumbersMax != 3 {
+		t.Errorf("options leaked: --input %s --min %d --max %d", generateNumbersInput, generateNumbersMin, generateNumbersMax)
+	}
+
+	if generateNumbersCmd.Flags().Changed("max") {
+		t.Errorf("--max should not be marked as changed after the check")
+	}
+}
+
+func writeTestFile(t *testing.T, path string, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"siguma0013/reskk-dictionary/internal/dictionary"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// directoryConfigPath はディレクトリ単位の設定ファイルのパス
+var directoryConfigPath string
+
+// directoryConfig はディレクトリ（またはファイル）単位の設定
+type directoryConfig struct {
+	Collation string `yaml:"collation"`
+
+	// 以下はエントリの制約、未指定の時は制約なし
+	KeyLength       lengthRange `yaml:"key_length"`
+	CandidateLength lengthRange `yaml:"candidate_length"`
+	CharClasses     []string    `yaml:"char_classes"`
+}
+
+// lengthRange は文字数の範囲、0 の時はその側の制限なし
+type lengthRange struct {
+	Min int `yaml:"min"`
+	Max int `yaml:"max"`
+}
+
+// contains は length が範囲内か判定する
+func (r lengthRange) contains(length int) bool {
+	return (r.Min == 0 || length >= r.Min) && (r.Max == 0 || length <= r.Max)
+}
+
+func (r lengthRange) String() string {
+	switch {
+	case r.Max == 0:
+		return fmt.Sprintf("%d-", r.Min)
+	case r.Min == r.Max:
+		return fmt.Sprintf("%d", r.Min)
+	default:
+		return fmt.Sprintf("%d-%d", r.Min, r.Max)
+	}
+}
+
+// validate は設定値の誤りを検出する
+func (c directoryConfig) validate() error {
+	for name, r := range map[string]lengthRange{"key_length": c.KeyLength, "candidate_length": c.CandidateLength} {
+		if r.Min < 0 || r.Max < 0 || (r.Max != 0 && r.Min > r.Max) {
+			return fmt.Errorf("invalid %s: min %d, max %d", name, r.Min, r.Max)
+		}
+	}
+
+	for _, class := range c.CharClasses {
+		if _, ok := dictionary.CharClasses[class]; !ok {
+			return fmt.Errorf("unknown char class %q", class)
+		}
+	}
+
+	return nil
+}
+
+// overlay は親ディレクトリの設定 c に子ディレクトリの設定 child を重ねる
+// child で指定された項目のみ上書きする
+func (c directoryConfig) overlay(child directoryConfig) directoryConfig {
+	if child.Collation != "" {
+		c.Collation = child.Collation
+	}
+
+	if child.KeyLength != (lengthRange{}) {
+		c.KeyLength = child.KeyLength
+	}
+
+	if child.CandidateLength != (lengthRange{}) {
+		c.CandidateLength = child.CandidateLength
+	}
+
+	if child.CharClasses != nil {
+		c.CharClasses = child.CharClasses
+	}
+
+	return c
+}
+
+// loadDirectoryConfig はyamlからディレクトリ単位の設定を読み込む
+// 設定ファイルが存在しない時は空の設定を返す
+func loadDirectoryConfig(path string) (map[string]directoryConfig, error) {
+	configFile, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var config struct {
+		Directories map[string]directoryConfig `yaml:"directories"`
+	}
+
+	if err := yaml.Unmarshal(configFile, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	for dir, directory := range config.Directories {
+		if err := directory.validate(); err != nil {
+			return nil, fmt.Errorf("config %q: %w", dir, err)
+		}
+	}
+
+	return config.Directories, nil
+}
+
+// lookupDirectoryConfig は path を含むディレクトリの設定を浅い順に重ねて返す
+// 設定のキーはカレントディレクトリからの相対パスとして扱う
+func lookupDirectoryConfig(configs map[string]directoryConfig, path string) (directoryConfig, bool) {
+	target, err := filepath.Abs(path)
+	if err != nil {
+		return directoryConfig{}, false
+	}
+
+	var bases []string
+	matched := make(map[string]directoryConfig)
+
+	for dir, config := range configs {
+		base, err := filepath.Abs(dir)
+		if err != nil {
+			continue
+		}
+
+		if target != base && !strings.HasPrefix(target, base+string(filepath.Separator)) {
+			continue
+		}
+
+		bases = append(bases, base)
+		matched[base] = config
+	}
+
+	// より深いディレクトリの設定を優先する
+	slices.SortFunc(bases, func(a string, b string) int {
+		return len(a) - len(b)
+	})
+
+	var found directoryConfig
+
+	for _, base := range bases {
+		found = found.overlay(matched[base])
+	}
+
+	return found, len(bases) > 0
+}
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	dict "siguma0013/reskk-dictionary/pkg/dictionary"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+)
+
+// noReadingGroup は読みの無い単語の集計名
+const noReadingGroup = "(no reading)"
+
+// 欠落の理由
+const (
+	missingEntry     = "no entry"
+	missingCandidate = "no matching candidate"
+)
+
+// オプション
+var (
+	coverageDictionaryPath string
+	coverageFormat         string
+)
+
+var coverageCmd = &cobra.Command{
+	Use:   "coverage <wordlist>",
+	Short: "単語リストのうち辞書に無い単語を行ごとに報告するコマンド",
+	Long: `wordlist は1行1単語の形式で、次のどちらかで記述する (空行と # で始まる行は無視する)
+  - 単語のみ: 候補に単語を含むエントリがあるか確認する
+  - 読み<TAB>単語 (または空白区切り): 読みのエントリがあり、候補に単語を含むか確認する`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if coverageFormat != "text" && coverageFormat != "json" {
+			return fmt.Errorf("unknown format %q (available: text, json)", coverageFormat)
+		}
+
+		file, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+
+		defer file.Close()
+
+		words, err := readWordList(file)
+		if err != nil {
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+
+		entries, err := loadDictionary(coverageDictionaryPath)
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", coverageDictionaryPath, err)
+		}
+
+		report := checkCoverage(entries, words)
+
+		if coverageFormat == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetEscapeHTML(false)
+			encoder.SetIndent("", "  ")
+
+			return encoder.Encode(report)
+		}
+
+		printCoverage(os.Stdout, report)
+
+		return nil
+	},
+}
+
+func init() {
+	coverageCmd.Flags().StringVar(&coverageDictionaryPath, "dictionary", "merge_order.yml", "dictionary to check (merge order file, directory or merged file)")
+	coverageCmd.Flags().StringVar(&coverageFormat, "format", "text", "output format (text, json)")
+	rootCmd.AddCommand(coverageCmd)
+}
+
+// coverageWord は単語リストの1行
+type coverageWord struct {
+	Line    int    `json:"line"`
+	Reading string `json:"reading,omitempty"`
+	Word    string `json:"word"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// coverageReport は単語リストの確認結果
+type coverageReport struct {
+	Total   int                       `json:"total"`
+	Covered int                       `json:"covered"`
+	Missing map[string][]coverageWord `json:"missing"` // 追加先の行 (AllowInitials) ごとの欠落単語
+}
+
+// readWordList は単語リストを読み込む
+func readWordList(reader io.Reader) ([]coverageWord, error) {
+	scanner := newLineReader(reader)
+
+	var words []coverageWord
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if strings.Contains(line, "\t") {
+			fields = strings.Split(line, "\t")
+		}
+
+		switch len(fields) {
+		case 1:
+			words = append(words, coverageWord{Line: scanner.Line(), Word: fields[0]})
+		case 2:
+			words = append(words, coverageWord{Line: scanner.Line(), Reading: strings.TrimSpace(fields[0]), Word: strings.TrimSpace(fields[1])})
+		default:
+			return nil, fmt.Errorf("line %d: expected \"word\" or \"reading<TAB>word\"", scanner.Line())
+		}
+	}
+
+	return words, scanner.Err()
+}
+
+// checkCoverage は単語リストのうち辞書に無い単語を追加先の行ごとにまとめる
+func checkCoverage(entries []dict.Entry, words []coverageWord) coverageReport {
+	byKey := make(map[string][]string, len(entries))
+	candidates := make(map[string]bool)
+
+	for _, entry := range entries {
+		byKey[entry.Key] = entry.Value
+
+		for _, value := range entry.Value {
+			candidates[value] = true
+		}
+	}
+
+	gyo := initialGyo()
+	report := coverageReport{Total: len(words), Missing: make(map[string][]coverageWord)}
+
+	for _, word := range words {
+		group := noReadingGroup
+
+		if word.Reading == "" {
+			if candidates[word.Word] {
+				report.Covered++
+				continue
+			}
+
+			word.Reason = missingEntry
+		} else {
+			values, ok := byKey[word.Reading]
+
+			if ok && slices.Contains(values, word.Word) {
+				report.Covered++
+				continue
+			}
+
+			word.Reason = missingEntry
+			if ok {
+				word.Reason = missingCandidate
+			}
+
+			initial, _ := utf8.DecodeRuneInString(word.Reading)
+
+			group, ok = gyo[string(initial)]
+			if !ok {
+				group = otherGyo
+			}
+		}
+
+		report.Missing[group] = append(report.Missing[group], word)
+	}
+
+	return report
+}
+
+// printCoverage は確認結果をテキストで出力する
+func printCoverage(writer io.Writer, report coverageReport) {
+	for _, group := range slices.Sorted(maps.Keys(report.Missing)) {
+		fmt.Fprintf(writer, "%s:\n", group)
+
+		for _, word := range report.Missing[group] {
+			if word.Reading == "" {
+				fmt.Fprintf(writer, "  - %s (%s, line %d)\n", word.Word, word.Reason, word.Line)
+				continue
+			}
+
+			fmt.Fprintf(writer, "  - %s %s (%s, line %d)\n", word.Reading, word.Word, word.Reason, word.Line)
+		}
+	}
+
+	rate := 100.0
+	if report.Total > 0 {
+		rate = float64(report.Covered) / float64(report.Total) * 100
+	}
+
+	fmt.Fprintf(writer, "Coverage: %d/%d (%.1f%%)\n", report.Covered, report.Total, rate)
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"bytes"
+	dict "siguma0013/reskk-dictionary/pkg/dictionary"
+	"strings"
+	"testing"
+)
+
+func TestCheckCoverage(t *testing.T) {
+	entries := []dict.Entry{
+		{Key: "きのう", Value: []string{"機能", "昨日"}},
+		{Key: "さくじょ", Value: []string{"削除"}},
+	}
+
+	words, err := readWordList(strings.NewReader(strings.Join([]string{
+		"# reading and word",
+		"きのう\t機能",
+		"きのう\t帰納",
+		"かいぎ 会議",
+		"",
+		"削除",
+		"暗号",
+	}, "\n")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	report := checkCoverage(entries, words)
+
+	if report.Total != 5 || report.Covered != 2 {
+		t.Fatalf("unexpected totals: %+v", report)
+	}
+
+	var output bytes.Buffer
+	printCoverage(&output, report)
+
+	expected := strings.Join([]string{
+		"(no reading):",
+		"  - 暗号 (no entry, line 7)",
+		"02-ka:",
+		"  - きのう 帰納 (no matching candidate, line 3)",
+		"  - かいぎ 会議 (no entry, line 4)",
+		"Coverage: 2/5 (40.0%)",
+		"",
+	}, "\n")
+
+	if output.String() != expected {
+		t.Fatalf("unexpected report:\n%s", output.String())
+	}
+}
+
+func TestReadWordList_Invalid(t *testing.T) {
+	if _, err := readWordList(strings.NewReader("a b c")); err == nil {
+		t.Fatalf("expected error for line with too many fields")
+	}
+}
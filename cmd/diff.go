@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	dict "siguma0013/reskk-dictionary/pkg/dictionary"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// オプション
+var (
+	diffFormat string
+)
+
+var diffCmd = &cobra.Command{
+	Use:          "diff <old> <new>",
+	Short:        "2つの辞書 (ディレクトリ、マージ済みファイル、merge_order.yml) をエントリ単位で比較するコマンド",
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if diffFormat != "text" && diffFormat != "json" {
+			return fmt.Errorf("unknown format %q (available: text, json)", diffFormat)
+		}
+
+		before, err := loadDiffEntries(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", args[0], err)
+		}
+
+		after, err := loadDiffEntries(args[1])
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", args[1], err)
+		}
+
+		result := dict.Diff(before, after)
+
+		if diffFormat == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetEscapeHTML(false)
+			encoder.SetIndent("", "  ")
+
+			return encoder.Encode(result)
+		}
+
+		printDiff(os.Stdout, result)
+
+		return nil
+	},
+}
+
+func init() {
+	diffCmd.Flags().StringVar(&diffFormat, "format", "text", "output format (text, json)")
+	rootCmd.AddCommand(diffCmd)
+}
+
+// loadDiffEntries は diff で比較する path の辞書を読み込む
+// ディレクトリはファイルごとにマージしたエントリを並べて返す
+// 複数のファイルにあるキーの候補の並び順はファイルの分け方で変わるため、Diff で並び順の変化として扱わないようにする
+func loadDiffEntries(path string) ([]dict.Entry, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return loadDictionary(path)
+	}
+
+	paths, err := resolveDictionaryFiles(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var denyPaths []string
+
+	for _, file := range paths {
+		if dict.IsDenyFile(file) {
+			denyPaths = append(denyPaths, file)
+		}
+	}
+
+	var entries []dict.Entry
+
+	for _, file := range paths {
+		if dict.IsDenyFile(file) {
+			continue
+		}
+
+		// 削除リストはファイルごとに適用する
+		fileEntries, err := makeMergeData(append([]string{file}, denyPaths...))
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, fileEntries...)
+	}
+
+	return entries, nil
+}
+
+// printDiff は差分をテキストで出力する
+//   - "+ よみ: 候補 / 候補" 追加されたキー
+//   - "- よみ: 候補 / 候補" 削除されたキー
+//   - "~ よみ: +候補 -候補 (reordered)" 候補が変わったキー
+func printDiff(writer io.Writer, result dict.DiffResult) {
+	for _, entry := range result.Added {
+		fmt.Fprintf(writer, "+ %s: %s\n", entry.Key, strings.Join(entry.Value, " / "))
+	}
+
+	for _, entry := range result.Removed {
+		fmt.Fprintf(writer, "- %s: %s\n", entry.Key, strings.Join(entry.Value, " / "))
+	}
+
+	for _, change := range result.Changed {
+		var parts []string
+
+		for _, value := range change.Added {
+			parts = append(parts, "+"+value)
+		}
+
+		for _, value := range change.Removed {
+			parts = append(parts, "-"+value)
+		}
+
+		if change.Reordered {
+			parts = append(parts, "(reordered: "+strings.Join(change.After, " / ")+")")
+		}
+
+		fmt.Fprintf(writer, "~ %s: %s\n", change.Key, strings.Join(parts, " "))
+	}
+
+	fmt.Fprintf(writer, "%d added, %d removed, %d changed\n", len(result.Added), len(result.Removed), len(result.Changed))
+}
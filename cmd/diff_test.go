@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	dict "siguma0013/reskk-dictionary/pkg/dictionary"
+	"testing"
+)
+
+func TestLoadDictionary_TreeAndFile(t *testing.T) {
+	d := t.TempDir()
+
+	if err := os.MkdirAll(filepath.Join(d, "tree", "sub"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	files := map[string]string{
+		"tree/a.jsonl":     `{"key": "きのう", "value": ["機能"]}` + "\n",
+		"tree/sub/b.jsonl": `{"key": "あい", "value": ["愛"]}` + "\n" + `{"key": "きのう", "value": ["昨日"]}` + "\n",
+		"merged.jsonl":     `{"key":"きのう","value":["昨日","機能"]}` + "\n" + `{"key":"あい","value":["愛"]}` + "\n",
+	}
+
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(d, name), []byte(data), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	tree, err := loadDictionary(filepath.Join(d, "tree"))
+	if err != nil {
+		t.Fatalf("load tree: %v", err)
+	}
+
+	merged, err := loadDictionary(filepath.Join(d, "merged.jsonl"))
+	if err != nil {
+		t.Fatalf("load merged: %v", err)
+	}
+
+	result := dict.Diff(tree, merged)
+	if len(result.Added) != 0 || len(result.Removed) != 0 || len(result.Changed) != 1 || !result.Changed[0].Reordered {
+		t.Fatalf("expected only a reorder of きのう, got %+v", result)
+	}
+
+	var output bytes.Buffer
+	printDiff(&output, result)
+
+	expected := "~ きのう: (reordered: 昨日 / 機能)\n0 added, 0 removed, 1 changed\n"
+	if output.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, output.String())
+	}
+}
+
+func TestLoadDiffEntries_Layout(t *testing.T) {
+	d := t.TempDir()
+
+	// 同じ内容でファイルの分け方だけが異なる2つのツリー
+	files := map[string]string{
+		"old/a.jsonl":       `{"key": "きのう", "value": ["機能"]}` + "\n",
+		"old/b.jsonl":       `{"key": "あい", "value": ["愛"]}` + "\n" + `{"key": "きのう", "value": ["昨日", "帰納"]}` + "\n",
+		"old/b.deny.jsonl":  `{"key": "きのう", "value": ["帰納"]}` + "\n",
+		"new/a.jsonl":       `{"key": "あい", "value": ["愛"]}` + "\n" + `{"key": "きのう", "value": ["昨日"]}` + "\n",
+		"new/b.jsonl":       `{"key": "きのう", "value": ["機能"]}` + "\n",
+		"new/c.jsonl":       `{"key": "きのう", "value": ["帰納"]}` + "\n",
+		"new/c.deny.jsonl":  `{"key": "きのう", "value": ["帰納"]}` + "\n",
+		"reordered/a.jsonl": `{"key": "あい", "value": ["愛"]}` + "\n" + `{"key": "きのう", "value": ["昨日", "機能"]}` + "\n",
+		"original/a.jsonl":  `{"key": "あい", "value": ["愛"]}` + "\n" + `{"key": "きのう", "value": ["機能", "昨日"]}` + "\n",
+	}
+
+	for name, data := range files {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(d, name)), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+
+		writeTestFile(t, filepath.Join(d, name), data)
+	}
+
+	load := func(name string) []dict.Entry {
+		entries, err := loadDiffEntries(filepath.Join(d, name))
+		if err != nil {
+			t.Fatalf("load %s: %v", name, err)
+		}
+
+		return entries
+	}
+
+	if result := dict.Diff(load("old"), load("new")); !result.Empty() {
+		t.Fatalf("expected no diff between layouts, got %+v", result)
+	}
+
+	// 1つのファイル内の並び順の変化は報告する
+	result := dict.Diff(load("original"), load("reordered"))
+	if len(result.Changed) != 1 || !result.Changed[0].Reordered {
+		t.Fatalf("expected a reorder of きのう, got %+v", result)
+	}
+}
@@ -1,27 +1,40 @@
 package cmd
 
 import (
-	"bufio"
 	"encoding/json"
 	"fmt"
 	"io"
 	"siguma0013/reskk-dictionary/internal/dictionary"
 	"siguma0013/reskk-dictionary/internal/utility"
+	dict "siguma0013/reskk-dictionary/pkg/dictionary"
+	"slices"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/spf13/cobra"
 )
 
 var formatCheckCmd = &cobra.Command{
-	Use:          "format",
-	Short:        "辞書ファイルのフォーマットをチェックするコマンド",
+	Use:   "format",
+	Short: "辞書ファイルのフォーマットをチェックするコマンド",
+	Long: `辞書ファイルのフォーマットをチェックする
+
+directory_config.yml のディレクトリ単位の制約 (key_length, candidate_length, char_classes) もチェックする
+チェックコマンドのうち制約をチェックするのはこのコマンドだけで、initial, sort, reading コマンドはチェックしない`,
 	Args:         cobra.MaximumNArgs(1),
 	SilenceUsage: true,
 	RunE: func(cmd *cobra.Command, args []string) error { // 実行時に呼ばれる関数（エラーを返せる）
 		filePath := args[0]
 
+		configs, err := loadDirectoryConfig(directoryConfigPath)
+		if err != nil {
+			return err
+		}
+
 		results := utility.WalkJsonl(filePath, nil, func(path string, file io.Reader) []error {
-			return checkFormat(file)
+			config, _ := lookupDirectoryConfig(configs, path)
+
+			return checkFormatWith(file, config, dict.IsDenyFile(path))
 		})
 
 		if utility.PrintResults(results) {
@@ -40,14 +53,24 @@ func init() {
 
 // checkFormat は辞書ファイルのフォーマットチェック本体
 func checkFormat(reader io.Reader) []error {
-	scanner := bufio.NewScanner(reader)
-	lineCount := 0
+	return checkFormatWith(reader, directoryConfig{}, false)
+}
+
+// checkFormatWith はフォーマットに加えてディレクトリ設定の制約をチェックする
+// deny の時は削除リスト (.deny.jsonl) として value の省略を許可する
+func checkFormatWith(reader io.Reader, config directoryConfig, deny bool) []error {
+	validate := dict.Validate
+	if deny {
+		validate = dict.ValidateDeny
+	}
+
+	scanner := newLineReader(reader)
 
 	var results []error
 
 	// 1行づつ繰り返し処理
 	for scanner.Scan() {
-		lineCount++
+		lineCount := scanner.Line()
 
 		line := scanner.Text()
 
@@ -66,22 +89,16 @@ func checkFormat(reader io.Reader) []error {
 		decoder := json.NewDecoder(strings.NewReader(line))
 		decoder.DisallowUnknownFields()
 
-		var record dictionary.Entry
+		var record dict.Entry
 
 		if decodeError := decoder.Decode(&record); decodeError != nil {
 			results = append(results, fmt.Errorf("line %d: schema error", lineCount))
 			continue
 		}
 
-		// keyの有無
-		if record.Key == "" {
-			results = append(results, fmt.Errorf("line %d: empty key", lineCount))
-			continue
-		}
-
-		// valueの有無
-		if len(record.Value) == 0 {
-			results = append(results, fmt.Errorf("line %d: empty value", lineCount))
+		// key, valueの有無
+		if err := validate(record); err != nil {
+			results = append(results, fmt.Errorf("line %d: %w", lineCount, err))
 			continue
 		}
 
@@ -90,11 +107,47 @@ func checkFormat(reader io.Reader) []error {
 				results = append(results, fmt.Errorf("line %d: %v", lineCount, rule.Message))
 			}
 		}
+
+		for _, err := range checkConstraints(record, config) {
+			results = append(results, fmt.Errorf("line %d: %w", lineCount, err))
+		}
 	}
 
-	if scannerError := scanner.Err(); scannerError != nil { // Scanner 自身のエラー（IO エラー等）をチェック
+	if scannerError := scanner.Err(); scannerError != nil { // 読み込み自身のエラー（IO エラー、長すぎる行等）をチェック
 		results = append(results, fmt.Errorf("scanner error: %w", scannerError))
 	}
 
 	return results
 }
+
+// checkConstraints はエントリがディレクトリ設定の制約を満たすかチェックする
+func checkConstraints(record dict.Entry, config directoryConfig) []error {
+	var results []error
+
+	if length := utf8.RuneCountInString(record.Key); !config.KeyLength.contains(length) {
+		results = append(results, fmt.Errorf("key %q length %d is out of range %s", record.Key, length, config.KeyLength))
+	}
+
+	for _, value := range record.Value {
+		if length := utf8.RuneCountInString(value); !config.CandidateLength.contains(length) {
+			results = append(results, fmt.Errorf("candidate %q length %d is out of range %s", value, length, config.CandidateLength))
+		}
+
+		if len(config.CharClasses) == 0 {
+			continue
+		}
+
+		for _, r := range value {
+			allowed := slices.ContainsFunc(config.CharClasses, func(class string) bool {
+				return dictionary.CharClasses[class](r)
+			})
+
+			if !allowed {
+				results = append(results, fmt.Errorf("candidate %q contains %q outside of %s", value, string(r), strings.Join(config.CharClasses, ", ")))
+				break
+			}
+		}
+	}
+
+	return results
+}
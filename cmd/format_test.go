@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	dict "siguma0013/reskk-dictionary/pkg/dictionary"
 	"strings"
 	"testing"
 )
@@ -45,3 +46,102 @@ func TestFormatCheck_Invalid(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatEntry(t *testing.T) {
+	line, err := dict.Format(dict.Entry{
+		Key:    "あんど",
+		Value:  []string{"&", "<\"b\">"},
+		Weight: map[string]int{"&": 2},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `{"key": "あんど", "value": ["&", "<\"b\">"], "weight": {"&": 2}}`
+	if line != expected {
+		t.Fatalf("expected %s, got %s", expected, line)
+	}
+
+	if validateError := checkFormat(strings.NewReader(line)); len(validateError) != 0 {
+		t.Fatalf("expected formatted line to pass format check, got %v", validateError)
+	}
+}
+
+// longEntryLine は bufio.Scanner の既定上限 (64KB) を超える1行を作成する
+func longEntryLine(key string) string {
+	values := make([]string, 20000)
+	for i := range values {
+		values[i] = `"機能"`
+	}
+
+	return `{"key": "` + key + `", "value": [` + strings.Join(values, ", ") + `]}`
+}
+
+func TestFormatCheck_LongLine(t *testing.T) {
+	reader := strings.NewReader(strings.Join([]string{
+		`{"key": "あい", "value": ["愛"]}`,
+		longEntryLine("きのう"),
+		`{"key":"きのう", "value": ["機能"]}`,
+	}, "\n"))
+
+	validateError := checkFormat(reader)
+	if len(validateError) != 1 || !strings.HasPrefix(validateError[0].Error(), "line 3:") {
+		t.Fatalf("expected 1 error on line 3, got %v", validateError)
+	}
+}
+
+func TestFormatCheck_MaxLineSize(t *testing.T) {
+	maxLineSize = 1024
+	defer func() { maxLineSize = 0 }()
+
+	reader := strings.NewReader(strings.Join([]string{
+		`{"key": "あい", "value": ["愛"]}`,
+		longEntryLine("きのう"),
+	}, "\n"))
+
+	validateError := checkFormat(reader)
+	if len(validateError) != 1 || !strings.Contains(validateError[0].Error(), "line 2: line too long") {
+		t.Fatalf("expected line too long error on line 2, got %v", validateError)
+	}
+}
+
+func TestFormatCheck_Constraints(t *testing.T) {
+	config := directoryConfig{
+		KeyLength:       lengthRange{Min: 2},
+		CandidateLength: lengthRange{Min: 2, Max: 2},
+		CharClasses:     []string{"kanji"},
+	}
+
+	reader := strings.NewReader(strings.Join([]string{
+		`{"key": "あんごう", "value": ["暗号"]}`,
+		`{"key": "あ", "value": ["亜"]}`,
+		`{"key": "あんごうか", "value": ["暗号化"]}`,
+		`{"key": "あいでぃー", "value": ["ＩＤ"]}`,
+	}, "\n"))
+
+	validateError := checkFormatWith(reader, config, false)
+	if len(validateError) != 4 {
+		t.Fatalf("expected 4 errors, got %v", validateError)
+	}
+
+	for index, prefix := range []string{"line 2:", "line 2:", "line 3:", "line 4:"} {
+		if !strings.HasPrefix(validateError[index].Error(), prefix) {
+			t.Fatalf("expected error %d on %s, got %v", index, prefix, validateError[index])
+		}
+	}
+}
+
+func TestFormatCheck_Deny(t *testing.T) {
+	lines := strings.Join([]string{
+		`{"key": "きのう", "value": ["昨日"]}`,
+		`{"key": "あい"}`,
+	}, "\n")
+
+	if validateError := checkFormatWith(strings.NewReader(lines), directoryConfig{}, true); len(validateError) != 0 {
+		t.Fatalf("expected no errors for deny list, got %v", validateError)
+	}
+
+	if validateError := checkFormatWith(strings.NewReader(lines), directoryConfig{}, false); len(validateError) != 1 {
+		t.Fatalf("expected missing value error, got %v", validateError)
+	}
+}
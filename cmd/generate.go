@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	dict "siguma0013/reskk-dictionary/pkg/dictionary"
+	"slices"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// generatedManifestHeader は生成ファイル一覧の先頭に付けるコメント
+const generatedManifestHeader = "# 生成された辞書ファイルの一覧 (generate コマンドが更新するため手で編集しないこと)\n# check --generated で再生成した結果と一致するか確認する\n"
+
+// generatedManifestPath は生成ファイル一覧のパス
+var generatedManifestPath string
+
+// generators は生成コマンド名と生成処理の対応
+// 生成処理はコマンドのオプション (パッケージ変数) を参照し、生成したエントリと入力ファイルを返す
+var generators = map[string]func() ([]dict.Entry, []string, error){}
+
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "規則から辞書データを生成するコマンド",
+}
+
+func init() {
+	generateCmd.PersistentFlags().StringVar(&generatedManifestPath, "manifest", "generated.yml", "manifest of generated files")
+	rootCmd.AddCommand(generateCmd)
+}
+
+// generatedManifest は生成ファイル一覧
+type generatedManifest struct {
+	Files map[string]generatedFile `yaml:"files"`
+}
+
+// generatedFile は生成ファイル1つ分の生成元の記録
+type generatedFile struct {
+	Generator string            `yaml:"generator"`         // generate のサブコマンド名
+	Options   map[string]string `yaml:"options,omitempty"` // 生成時のオプション (--output, --help 以外)
+	Inputs    map[string]string `yaml:"inputs,omitempty"`  // 入力ファイルとそのハッシュ
+	Hash      string            `yaml:"hash"`              // 生成ファイルのハッシュ
+}
+
+// runGenerator は生成コマンド cmd の生成処理を実行し、path ("-" は標準出力) に書き出す
+// ファイルに書き出した時は生成ファイル一覧に生成元を記録する
+func runGenerator(cmd *cobra.Command, path string) error {
+	entries, inputs, err := generators[cmd.Name()]()
+	if err != nil {
+		return err
+	}
+
+	content, err := formatGenerated(entries)
+	if err != nil {
+		return err
+	}
+
+	if path == "-" {
+		_, err := os.Stdout.Write(content)
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	record := generatedFile{
+		Generator: cmd.Name(),
+		Options:   make(map[string]string),
+		Inputs:    make(map[string]string),
+		Hash:      contentHash(content),
+	}
+
+	cmd.LocalNonPersistentFlags().VisitAll(func(flag *pflag.Flag) {
+		if flag.Name != "output" && flag.Name != "help" {
+			record.Options[flag.Name] = flag.Value.String()
+		}
+	})
+
+	for _, input := range inputs {
+		inputContent, err := os.ReadFile(input)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", input, err)
+		}
+
+		record.Inputs[input] = contentHash(inputContent)
+	}
+
+	manifest, err := loadGeneratedManifest(generatedManifestPath)
+	if err != nil {
+		return err
+	}
+
+	manifest.Files[filepath.ToSlash(path)] = record
+
+	return saveGeneratedManifest(generatedManifestPath, manifest)
+}
+
+// formatGenerated は生成したエントリをまとめて五十音順に並べ、正規フォーマットの内容を作成する
+func formatGenerated(entries []dict.Entry) ([]byte, error) {
+	entries = dict.Normalize(entries, dict.NormalizeOptions{Dedupe: true})
+	dict.Sort(entries, dict.Compare)
+
+	return formatEntries(entries)
+}
+
+// formatEntries はエントリを並び順のまま正規フォーマットの内容にする
+func formatEntries(entries []dict.Entry) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	writer := dict.NewWriter(&buffer)
+
+	for _, entry := range entries {
+		if err := writer.Write(entry); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// contentHash はファイル内容のハッシュを "sha256:<hex>" 形式で返す
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// loadGeneratedManifest は生成ファイル一覧を読み込む
+// ファイルが存在しない時は空の一覧を返す
+func loadGeneratedManifest(path string) (generatedManifest, error) {
+	manifest := generatedManifest{Files: make(map[string]generatedFile)}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return manifest, nil
+	}
+
+	if err != nil {
+		return manifest, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	if err := yaml.Unmarshal(content, &manifest); err != nil {
+		return manifest, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	if manifest.Files == nil {
+		manifest.Files = make(map[string]generatedFile)
+	}
+
+	return manifest, nil
+}
+
+// saveGeneratedManifest は生成ファイル一覧を書き出す
+func saveGeneratedManifest(path string, manifest generatedManifest) error {
+	buffer := bytes.NewBufferString(generatedManifestHeader)
+
+	encoder := yaml.NewEncoder(buffer)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(manifest); err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	return os.WriteFile(path, buffer.Bytes(), 0o644)
+}
+
+// findGenerator は生成コマンド名からサブコマンドを探す
+func findGenerator(name string) (*cobra.Command, bool) {
+	index := slices.IndexFunc(generateCmd.Commands(), func(cmd *cobra.Command) bool {
+		return cmd.Name() == name
+	})
+
+	if index < 0 || generators[name] == nil {
+		return nil, false
+	}
+
+	return generateCmd.Commands()[index], true
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"siguma0013/reskk-dictionary/internal/dictionary"
+	dict "siguma0013/reskk-dictionary/pkg/dictionary"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// オプション
+var (
+	generateCountersNumbers string
+	generateCountersInput   string
+	generateCountersOutput  string
+)
+
+var counterSokuon = dictionary.CounterSokuon()
+
+var generateCountersCmd = &cobra.Command{
+	Use:          "counters",
+	Short:        "数と助数詞の組み合わせ (いっかい → 一回 / 1回) を生成するコマンド",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runGenerator(cmd, generateCountersOutput)
+	},
+}
+
+func init() {
+	generateCountersCmd.Flags().StringVar(&generateCountersNumbers, "numbers", "jsonl/number.jsonl", "digit and unit readings")
+	generateCountersCmd.Flags().StringVar(&generateCountersInput, "input", "jsonl/number_word.jsonl", "counter readings")
+	generateCountersCmd.Flags().StringVar(&generateCountersOutput, "output", "jsonl/number_counter.jsonl", "output file (- for stdout)")
+	generateCmd.AddCommand(generateCountersCmd)
+	generators["counters"] = generateCounters
+}
+
+// generateCounters は助数詞と数の組み合わせを生成する
+func generateCounters() ([]dict.Entry, []string, error) {
+	numbersFile, err := os.Open(generateCountersNumbers)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", generateCountersNumbers, err)
+	}
+
+	defer numbersFile.Close()
+
+	numerals, err := loadNumerals(numbersFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", generateCountersNumbers, err)
+	}
+
+	counters, err := readEntries(generateCountersInput)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return counterEntries(numerals, counters), []string{generateCountersNumbers, generateCountersInput}, nil
+}
+
+// counterEntries は数と助数詞の組み合わせのエントリを作成する
+// 候補は漢数字 (一回) と算用数字 (1回) の2つ
+func counterEntries(numerals map[int][]numberReading, counters []dict.Entry) []dict.Entry {
+	var entries []dict.Entry
+
+	for _, counter := range counters {
+		for _, kanji := range counter.Value {
+			rule := dictionary.CounterRules[kanji]
+
+			max := rule.Max
+			if max == 0 {
+				max = dictionary.DefaultCounterMax
+			}
+
+			for n := 1; n <= max; n++ {
+				values := []string{readNumber(numerals, n)[0].kanji + kanji, strconv.Itoa(n) + kanji}
+
+				for _, reading := range readCounter(numerals, n, counter.Key, rule) {
+					entries = append(entries, dict.Entry{Key: reading, Value: values})
+				}
+			}
+		}
+	}
+
+	return entries
+}
+
+// readCounter は数 n に助数詞の読み counter を続けた読みを全て作成する
+func readCounter(numerals map[int][]numberReading, n int, counter string, rule dictionary.CounterRule) []string {
+	if readings, ok := rule.Readings[n]; ok {
+		return readings
+	}
+
+	var readings []string
+
+	for _, number := range readNumber(numerals, n) {
+		readings = append(readings, joinCounter(number.reading, counter, rule)...)
+	}
+
+	return readings
+}
+
+// joinCounter は数の読みに助数詞の読みを続け、促音化・連濁を適用する
+//   - 促音化: いち + かい → いっかい、は行の助数詞は半濁音になる (いっぽん)
+//   - 連濁: CounterRule.Rendaku の時、さん の後で濁音になる (さんがい)
+func joinCounter(number string, counter string, rule dictionary.CounterRule) []string {
+	runes := []rune(counter)
+	head := runes[0]
+	rest := string(runes[1:])
+
+	if !rule.Native {
+		for _, ending := range counterSokuon[head] {
+			if !strings.HasSuffix(number, ending) {
+				continue
+			}
+
+			// 半濁音のある は行 は促音の後で半濁音にする
+			changedHead := head
+			if voiced := rendakuKana[head]; len(voiced) == 2 {
+				changedHead = voiced[1]
+			}
+
+			var readings []string
+
+			for _, sokuon := range dictionary.SokuonEndings[ending] {
+				readings = append(readings, strings.TrimSuffix(number, ending)+sokuon+string(changedHead)+rest)
+			}
+
+			return readings
+		}
+	}
+
+	if rule.Rendaku && strings.HasSuffix(number, "さん") {
+		if voiced := rendakuKana[head]; len(voiced) > 0 {
+			return []string{number + string(voiced[0]) + rest}
+		}
+	}
+
+	return []string{number + counter}
+}
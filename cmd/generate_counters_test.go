@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"siguma0013/reskk-dictionary/internal/dictionary"
+	dict "siguma0013/reskk-dictionary/pkg/dictionary"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestJoinCounter(t *testing.T) {
+	tests := []struct {
+		number   string
+		counter  string
+		rule     dictionary.CounterRule
+		expected []string
+	}{
+		{"いち", "かい", dictionary.CounterRule{}, []string{"いっかい"}},
+		{"さん", "かい", dictionary.CounterRule{}, []string{"さんかい"}},
+		{"さん", "かい", dictionary.CounterRule{Rendaku: true}, []string{"さんがい"}},
+		{"はち", "がつ", dictionary.CounterRule{}, []string{"はちがつ"}},
+		{"ろく", "ほん", dictionary.CounterRule{Rendaku: true}, []string{"ろっぽん"}},
+		{"さん", "ほん", dictionary.CounterRule{Rendaku: true}, []string{"さんぼん"}},
+		{"じゅう", "こ", dictionary.CounterRule{}, []string{"じゅっこ", "じっこ"}},
+		{"にじゅういち", "こ", dictionary.CounterRule{}, []string{"にじゅういっこ"}},
+		{"ろく", "さつ", dictionary.CounterRule{}, []string{"ろくさつ"}},
+		{"いち", "くみ", dictionary.CounterRule{Native: true}, []string{"いちくみ"}},
+	}
+
+	for _, test := range tests {
+		if readings := joinCounter(test.number, test.counter, test.rule); !slices.Equal(readings, test.expected) {
+			t.Fatalf("%s + %s: expected %v, got %v", test.number, test.counter, test.expected, readings)
+		}
+	}
+}
+
+func TestReadCounter_Exception(t *testing.T) {
+	rule := dictionary.CounterRule{Readings: map[int][]string{1: {"ついたち"}}}
+
+	if readings := readCounter(nil, 1, "にち", rule); !slices.Equal(readings, []string{"ついたち"}) {
+		t.Fatalf("expected exception reading, got %v", readings)
+	}
+}
+
+func TestCounterEntries(t *testing.T) {
+	numerals, err := loadNumerals(strings.NewReader(strings.Join([]string{
+		`{"key": "いち", "value": ["一", "壱"]}`,
+		`{"key": "に", "value": ["二", "弐"]}`,
+		`{"key": "さん", "value": ["三", "参"]}`,
+		`{"key": "よん", "value": ["四", "肆"]}`,
+		`{"key": "ご", "value": ["五", "伍"]}`,
+		`{"key": "ろく", "value": ["六", "陸"]}`,
+		`{"key": "なな", "value": ["七", "漆"]}`,
+		`{"key": "はち", "value": ["八", "捌"]}`,
+		`{"key": "きゅう", "value": ["九", "玖"]}`,
+		`{"key": "じゅう", "value": ["十", "拾"]}`,
+		`{"key": "ひゃく", "value": ["百", "佰"]}`,
+		`{"key": "せん", "value": ["千", "阡"]}`,
+		`{"key": "まん", "value": ["万", "萬"]}`,
+	}, "\n")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	counters := []dict.Entry{
+		{Key: "かい", Value: []string{"回", "階"}},
+		{Key: "にち", Value: []string{"日"}},
+	}
+
+	values := make(map[string][]string)
+
+	for _, entry := range counterEntries(numerals, counters) {
+		values[entry.Key] = append(values[entry.Key], entry.Value...)
+	}
+
+	tests := []struct {
+		key      string
+		expected []string
+	}{
+		{"いっかい", []string{"一回", "1回", "一階", "1階"}},
+		{"さんかい", []string{"三回", "3回"}},
+		{"さんがい", []string{"三階", "3階"}},
+		{"ついたち", []string{"一日", "1日"}},
+	}
+
+	for _, test := range tests {
+		if !slices.Equal(values[test.key], test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.key, test.expected, values[test.key])
+		}
+	}
+}
+
+func TestGenerateCounters_Repository(t *testing.T) {
+	defer func(numbers string, input string) {
+		generateCountersNumbers, generateCountersInput = numbers, input
+	}(generateCountersNumbers, generateCountersInput)
+
+	// リポジトリの number.jsonl と number_word.jsonl から生成する
+	generateCountersNumbers = "../jsonl/number.jsonl"
+	generateCountersInput = "../jsonl/number_word.jsonl"
+
+	entries, _, err := generateCounters()
+	if err != nil {
+		t.Fatalf("generateCounters: %v", err)
+	}
+
+	index := slices.IndexFunc(entries, func(entry dict.Entry) bool { return entry.Key == "さんがい" })
+	if index < 0 || !slices.Equal(entries[index].Value, []string{"三階", "3階"}) {
+		t.Errorf("さんがい is not generated as 三階 / 3階")
+	}
+}
@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"siguma0013/reskk-dictionary/internal/dictionary"
+	dict "siguma0013/reskk-dictionary/pkg/dictionary"
+
+	"github.com/spf13/cobra"
+)
+
+// maxGeneratedNumber は万の位までで読める最大の数
+const maxGeneratedNumber = 99999999
+
+// オプション
+var (
+	generateNumbersInput  string
+	generateNumbersOutput string
+	generateNumbersMin    int
+	generateNumbersMax    int
+)
+
+var generateNumbersCmd = &cobra.Command{
+	Use:          "numbers",
+	Short:        "数の読み (にじゅうさん → 二十三 / 弐拾参) を生成するコマンド",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runGenerator(cmd, generateNumbersOutput)
+	},
+}
+
+func init() {
+	generateNumbersCmd.Flags().StringVar(&generateNumbersInput, "input", "jsonl/number.jsonl", "digit and unit readings")
+	generateNumbersCmd.Flags().StringVar(&generateNumbersOutput, "output", "-", "output file (- for stdout)")
+	generateNumbersCmd.Flags().IntVar(&generateNumbersMin, "min", 1, "smallest number to generate")
+	generateNumbersCmd.Flags().IntVar(&generateNumbersMax, "max", 100, "largest number to generate")
+	generateCmd.AddCommand(generateNumbersCmd)
+	generators["numbers"] = generateNumbers
+}
+
+// generateNumbers は --min から --max までの数の読みを生成する
+func generateNumbers() ([]dict.Entry, []string, error) {
+	if generateNumbersMin < 1 || generateNumbersMin > generateNumbersMax || generateNumbersMax > maxGeneratedNumber {
+		return nil, nil, fmt.Errorf("invalid range %d-%d (must be within 1-%d)", generateNumbersMin, generateNumbersMax, maxGeneratedNumber)
+	}
+
+	file, err := os.Open(generateNumbersInput)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", generateNumbersInput, err)
+	}
+
+	defer file.Close()
+
+	numerals, err := loadNumerals(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", generateNumbersInput, err)
+	}
+
+	var entries []dict.Entry
+
+	for n := generateNumbersMin; n <= generateNumbersMax; n++ {
+		entries = append(entries, numberEntries(numerals, n)...)
+	}
+
+	return entries, []string{generateNumbersInput}, nil
+}
+
+// numberReading は数の読みと表記の組
+type numberReading struct {
+	reading string
+	kanji   string // 通常の漢数字
+	daiji   string // 大字
+}
+
+// loadNumerals は number.jsonl から数字と桁の読み・表記を読み込む
+// 1つ目の候補を漢数字、2つ目の候補を大字として扱う
+func loadNumerals(reader io.Reader) (map[int][]numberReading, error) {
+	entryReader := dict.NewReader(reader)
+	entryReader.SetMaxLineSize(maxLineSize)
+
+	numerals := make(map[int][]numberReading)
+
+	for {
+		entry, err := entryReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(entry.Value) == 0 {
+			continue
+		}
+
+		n, ok := dictionary.Numerals[entry.Value[0]]
+		if !ok {
+			continue
+		}
+
+		daiji := entry.Value[0]
+		if len(entry.Value) > 1 {
+			daiji = entry.Value[1]
+		}
+
+		numerals[n] = append(numerals[n], numberReading{reading: entry.Key, kanji: entry.Value[0], daiji: daiji})
+	}
+
+	for kanji, n := range dictionary.Numerals {
+		if len(numerals[n]) == 0 {
+			return nil, fmt.Errorf("missing reading of %s", kanji)
+		}
+	}
+
+	return numerals, nil
+}
+
+// numberEntries は n の全ての読みをエントリにする
+func numberEntries(numerals map[int][]numberReading, n int) []dict.Entry {
+	var entries []dict.Entry
+
+	for _, reading := range readNumber(numerals, n) {
+		entries = append(entries, dict.Entry{Key: reading.reading, Value: []string{reading.kanji, reading.daiji}})
+	}
+
+	return entries
+}
+
+// readNumber は n (1 から 99999999) の読みを全て作成する
+// 七 (しち / なな) のように読みが複数ある数字は全ての組み合わせを作成する
+func readNumber(numerals map[int][]numberReading, n int) []numberReading {
+	if n >= 10000 {
+		upper := withUnit(readGroup(numerals, n/10000), numerals[10000])
+
+		if n%10000 == 0 {
+			return upper
+		}
+
+		return joinReadings(upper, readGroup(numerals, n%10000))
+	}
+
+	return readGroup(numerals, n)
+}
+
+// readGroup は n (1 から 9999) の読みを全て作成する
+// 十・百・千 の前の 一 は読まない
+func readGroup(numerals map[int][]numberReading, n int) []numberReading {
+	readings := []numberReading{{}}
+
+	for _, place := range []int{1000, 100, 10, 1} {
+		digit := n / place % 10
+
+		switch {
+		case digit == 0:
+			continue
+		case place == 1:
+			readings = joinReadings(readings, numerals[digit])
+		case digit == 1:
+			readings = joinReadings(readings, numerals[place])
+		default:
+			readings = joinReadings(readings, withUnit(numerals[digit], numerals[place]))
+		}
+	}
+
+	return readings
+}
+
+// withUnit は数の読みに桁を続け、NumberSoundChanges の音便を適用する
+func withUnit(numbers []numberReading, units []numberReading) []numberReading {
+	readings := joinReadings(numbers, units)
+
+	for i, reading := range readings {
+		if changed, ok := dictionary.NumberSoundChanges[reading.reading]; ok {
+			readings[i].reading = changed
+		}
+	}
+
+	return readings
+}
+
+// joinReadings は heads と tails の全ての組み合わせを繋げる
+func joinReadings(heads []numberReading, tails []numberReading) []numberReading {
+	readings := make([]numberReading, 0, len(heads)*len(tails))
+
+	for _, head := range heads {
+		for _, tail := range tails {
+			readings = append(readings, numberReading{
+				reading: head.reading + tail.reading,
+				kanji:   head.kanji + tail.kanji,
+				daiji:   head.daiji + tail.daiji,
+			})
+		}
+	}
+
+	return readings
+}
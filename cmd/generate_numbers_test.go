@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestReadNumber(t *testing.T) {
+	numerals, err := loadNumerals(strings.NewReader(strings.Join([]string{
+		`{"key": "いち", "value": ["一", "壱"]}`,
+		`{"key": "に", "value": ["二", "弐"]}`,
+		`{"key": "さん", "value": ["三", "参"]}`,
+		`{"key": "よん", "value": ["四", "肆"]}`,
+		`{"key": "ご", "value": ["五", "伍"]}`,
+		`{"key": "ろく", "value": ["六", "陸"]}`,
+		`{"key": "しち", "value": ["七", "漆"]}`,
+		`{"key": "なな", "value": ["七", "漆"]}`,
+		`{"key": "はち", "value": ["八", "捌"]}`,
+		`{"key": "きゅう", "value": ["九", "玖"]}`,
+		`{"key": "じゅう", "value": ["十", "拾"]}`,
+		`{"key": "ひゃく", "value": ["百", "佰"]}`,
+		`{"key": "せん", "value": ["千", "阡"]}`,
+		`{"key": "まん", "value": ["万", "萬"]}`,
+	}, "\n")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		n        int
+		expected []numberReading
+	}{
+		{23, []numberReading{{"にじゅうさん", "二十三", "弐拾参"}}},
+		{10, []numberReading{{"じゅう", "十", "拾"}}},
+		{300, []numberReading{{"さんびゃく", "三百", "参佰"}}},
+		{800, []numberReading{{"はっぴゃく", "八百", "捌佰"}}},
+		{8000, []numberReading{{"はっせん", "八千", "捌阡"}}},
+		{17, []numberReading{{"じゅうしち", "十七", "拾漆"}, {"じゅうなな", "十七", "拾漆"}}},
+		{10003, []numberReading{{"いちまんさん", "一万三", "壱萬参"}}},
+	}
+
+	for _, test := range tests {
+		if readings := readNumber(numerals, test.n); !slices.Equal(readings, test.expected) {
+			t.Fatalf("%d: expected %v, got %v", test.n, test.expected, readings)
+		}
+	}
+}
+
+func TestLoadNumerals_Missing(t *testing.T) {
+	if _, err := loadNumerals(strings.NewReader(`{"key": "いち", "value": ["一", "壱"]}`)); err == nil {
+		t.Fatalf("expected missing numeral error")
+	}
+}
@@ -1,13 +1,13 @@
 package cmd
 
 import (
-	"bufio"
 	"encoding/json"
 	"fmt"
 	"io"
 	"path/filepath"
 	"siguma0013/reskk-dictionary/internal/dictionary"
 	"siguma0013/reskk-dictionary/internal/utility"
+	dict "siguma0013/reskk-dictionary/pkg/dictionary"
 	"slices"
 
 	"github.com/spf13/cobra"
@@ -65,16 +65,15 @@ func filterInitial(path string, root string) bool {
 
 // checkInitial 辞書ファイルの頭文字チェック関数
 func checkInitial(reader io.Reader, allowInitial []string) []error {
-	scanner := bufio.NewScanner(reader)
-	lineCount := 0
+	scanner := newLineReader(reader)
 
 	var results []error
 
 	// 1行づつ繰り返し処理
 	for scanner.Scan() {
-		lineCount++
+		lineCount := scanner.Line()
 
-		var record dictionary.Entry
+		var record dict.Entry
 
 		// パース
 		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
@@ -92,5 +91,9 @@ func checkInitial(reader io.Reader, allowInitial []string) []error {
 		}
 	}
 
+	if scannerError := scanner.Err(); scannerError != nil {
+		results = append(results, fmt.Errorf("scanner error: %w", scannerError))
+	}
+
 	return results
 }
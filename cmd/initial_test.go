@@ -31,3 +31,11 @@ func TestCheckInitial_InitialError(t *testing.T) {
 		t.Fatalf("expected initial error on line 1, got %v", errs)
 	}
 }
+
+func TestCheckInitial_LongLine(t *testing.T) {
+	data := longEntryLine("あい") + "\n" + `{"key":"かい","value":["v"]}`
+	errs := checkInitial(bytes.NewBufferString(data), []string{"あ", "い"})
+	if len(errs) != 1 || errs[0].Error() != "initial error: 2" {
+		t.Fatalf("expected initial error on line 2, got %v", errs)
+	}
+}
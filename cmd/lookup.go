@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"siguma0013/reskk-dictionary/internal/utility"
+	dict "siguma0013/reskk-dictionary/pkg/dictionary"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// オプション
+var (
+	isLookupPrefix bool
+	lookupReverse  string
+	lookupSource   string
+)
+
+// lookupHit は検索にヒットしたエントリとその定義位置
+type lookupHit struct {
+	Path  string
+	Line  int
+	Entry dict.Entry
+}
+
+var lookupCmd = &cobra.Command{
+	Use:          "lookup [reading]",
+	Short:        "辞書からエントリを検索し、定義されているファイルと行を表示するコマンド",
+	SilenceUsage: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		// 逆引きの時は読みを受け取らない
+		if lookupReverse != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		match := lookupMatcher(args, isLookupPrefix, lookupReverse)
+
+		var hits []lookupHit
+
+		results := utility.WalkJsonl(lookupSource, skipDenyFile, func(path string, file io.Reader) []error {
+			fileHits, err := lookupEntries(file, match)
+
+			for _, hit := range fileHits {
+				hit.Path = path
+				hits = append(hits, hit)
+			}
+
+			if err != nil {
+				return []error{err}
+			}
+
+			return nil
+		})
+
+		// 読み込めなかったファイルだけを報告する
+		results = slices.DeleteFunc(results, func(result utility.FileResult) bool {
+			return len(result.Errors) == 0
+		})
+
+		if utility.PrintResults(results) {
+			return fmt.Errorf("failed to read dictionary")
+		}
+
+		if len(hits) == 0 {
+			return fmt.Errorf("no entry found")
+		}
+
+		for _, hit := range hits {
+			line, err := dict.Format(hit.Entry)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("%s:%d: %s\n", hit.Path, hit.Line, line)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	lookupCmd.Flags().BoolVar(&isLookupPrefix, "prefix", false, "find entries whose reading starts with the given reading")
+	lookupCmd.Flags().StringVar(&lookupReverse, "reverse", "", "find readings which have the given candidate")
+	lookupCmd.Flags().StringVar(&lookupSource, "source", "jsonl", "source tree or merged file to search")
+	rootCmd.AddCommand(lookupCmd)
+}
+
+// lookupMatcher は検索条件からエントリの判定関数を作成する
+//   - reverse: 候補に reverse を含むエントリ
+//   - prefix: 読みが args[0] から始まるエントリ
+//   - それ以外: 読みが args[0] と一致するエントリ
+func lookupMatcher(args []string, prefix bool, reverse string) func(entry dict.Entry) bool {
+	if reverse != "" {
+		return func(entry dict.Entry) bool {
+			return slices.Contains(entry.Value, reverse)
+		}
+	}
+
+	reading := args[0]
+
+	if prefix {
+		return func(entry dict.Entry) bool {
+			return strings.HasPrefix(entry.Key, reading)
+		}
+	}
+
+	return func(entry dict.Entry) bool {
+		return entry.Key == reading
+	}
+}
+
+// lookupEntries は reader から match に一致するエントリを行番号付きで返す
+// パースできない行があっても検索は続け、最初のエラーを返す
+func lookupEntries(reader io.Reader, match func(entry dict.Entry) bool) ([]lookupHit, error) {
+	entryReader := dict.NewReader(reader)
+	entryReader.SetMaxLineSize(maxLineSize)
+
+	var hits []lookupHit
+	var firstError error
+
+	for {
+		entry, err := entryReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var parseError *dict.ParseError
+
+		// 行単位のパースエラーは記録して読み進める
+		if errors.As(err, &parseError) {
+			if firstError == nil {
+				firstError = err
+			}
+			continue
+		}
+
+		if err != nil {
+			return hits, err
+		}
+
+		if match(entry) {
+			hits = append(hits, lookupHit{Line: entryReader.Line(), Entry: entry})
+		}
+	}
+
+	return hits, firstError
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLookupEntries(t *testing.T) {
+	data := strings.Join([]string{
+		`{"key": "きのう", "value": ["機能", "昨日"]}`,
+		`not json`,
+		`{"key": "きのこ", "value": ["茸"]}`,
+		`{"key": "さくじつ", "value": ["昨日"]}`,
+	}, "\n")
+
+	tests := []struct {
+		name     string
+		args     []string
+		prefix   bool
+		reverse  string
+		expected []int
+	}{
+		{"exact", []string{"きのう"}, false, "", []int{1}},
+		{"prefix", []string{"きの"}, true, "", []int{1, 3}},
+		{"reverse", nil, false, "昨日", []int{1, 4}},
+		{"not found", []string{"きの"}, false, "", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hits, err := lookupEntries(strings.NewReader(data), lookupMatcher(test.args, test.prefix, test.reverse))
+
+			// パースできない行があっても検索は続ける
+			if err == nil || !strings.HasPrefix(err.Error(), "line 2:") {
+				t.Fatalf("expected parse error on line 2, got %v", err)
+			}
+
+			var lines []int
+			for _, hit := range hits {
+				lines = append(lines, hit.Line)
+			}
+
+			if len(lines) != len(test.expected) {
+				t.Fatalf("expected hits on lines %v, got %v", test.expected, lines)
+			}
+
+			for i := range lines {
+				if lines[i] != test.expected[i] {
+					t.Fatalf("expected hits on lines %v, got %v", test.expected, lines)
+				}
+			}
+		})
+	}
+}
+
+func TestLookupCommand_SkipsDenyFile(t *testing.T) {
+	defer func(source string) { lookupSource = source }(lookupSource)
+
+	dir := t.TempDir()
+	lookupSource = dir
+
+	writeTestFile(t, filepath.Join(dir, "a.jsonl"), `{"key": "きのう", "value": ["機能"]}`+"\n")
+	writeTestFile(t, filepath.Join(dir, "a.deny.jsonl"), `{"key": "さくじつ", "value": ["昨日"]}`+"\n")
+
+	if err := lookupCmd.RunE(lookupCmd, []string{"きのう"}); err != nil {
+		t.Fatalf("lookup failed: %v", err)
+	}
+
+	// 削除リストにしか無いキーは見つからない
+	if err := lookupCmd.RunE(lookupCmd, []string{"さくじつ"}); err == nil {
+		t.Fatalf("expected no entry for a key only in the deny list")
+	}
+}
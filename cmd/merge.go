@@ -2,12 +2,18 @@ package cmd
 
 import (
 	"bufio"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
 	"siguma0013/reskk-dictionary/internal/dictionary"
+	dict "siguma0013/reskk-dictionary/pkg/dictionary"
 	"slices"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
@@ -15,8 +21,13 @@ import (
 
 // オプション
 var (
-	mergeOrderPath  string
-	mergeOutputPath string
+	mergeOrderPath    string
+	mergeOutputPath   string
+	mergeCollation    string
+	mergeProfile      string
+	mergeAttribution  string
+	mergeUserPath     string
+	mergeUserEncoding string
 )
 
 var mergeCmd = &cobra.Command{
@@ -24,15 +35,61 @@ var mergeCmd = &cobra.Command{
 	Short: "Merge JSONL files according",
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		orders, err := makeMergeOrder(mergeOrderPath)
+		// profiles と attribution は merge_order.yml にしか書けないため、出力を作る前に確認する
+		if (mergeProfile != "" || mergeAttribution != "") && !isOrderFile(mergeOrderPath) {
+			return fmt.Errorf("--profile and --attribution require a merge order file (yml) as --input, got %s", mergeOrderPath)
+		}
+
+		orders, err := resolveDictionaryFiles(mergeOrderPath)
 
 		if err != nil {
-			return fmt.Errorf("nothing order %s: %w", mergeOrderPath, err)
+			return fmt.Errorf("failed to load %s: %w", mergeOrderPath, err)
 		}
 
-		mergeData, err := makeMergeData(orders)
+		compare, err := dict.Collation(mergeCollation)
 		if err != nil {
-			return fmt.Errorf("missing merge data")
+			return err
+		}
+
+		policy := mergePolicy(compare)
+
+		if mergeProfile != "" {
+			profile, err := loadMergeProfile(mergeOrderPath, mergeProfile)
+			if err != nil {
+				return err
+			}
+
+			policy.IncludeTags = profile.IncludeTags
+			policy.ExcludeTags = profile.ExcludeTags
+		}
+
+		var attribution map[string]dict.Provenance
+
+		if mergeAttribution != "" {
+			attribution, err = loadAttribution(mergeOrderPath)
+			if err != nil {
+				return err
+			}
+		}
+
+		merged := dict.Merge(dict.FileSources(orders), policy)
+
+		// 利用者の辞書を重ねる
+		var user []dict.Entry
+
+		if mergeUserPath != "" {
+			var skipped int
+
+			user, skipped, err = loadUserDictionary(mergeUserPath, mergeUserEncoding)
+			if err != nil {
+				return err
+			}
+
+			if skipped > 0 {
+				fmt.Fprintf(os.Stderr, "warning: %s: skipped %d okuri-ari entries (only okuri-nasi entries are merged)\n", mergeUserPath, skipped)
+			}
+
+			merged = dict.Overlay(merged, user, compare)
 		}
 
 		// これより出力処理
@@ -43,19 +100,25 @@ var mergeCmd = &cobra.Command{
 
 		defer outFile.Close()
 
-		for key, value := range mergeData {
-			jsonObject := dictionary.Entry{
-				Key:   key,
-				Value: value,
-			}
+		writer := dict.NewWriter(outFile)
 
-			jsonString, err := json.Marshal(jsonObject)
+		// マージ結果はメモリに溜めずに正規フォーマットでそのまま書き出す
+		for entry, err := range merged {
 			if err != nil {
-				return fmt.Errorf("missing make json string")
+				return fmt.Errorf("missing merge data: %w", err)
 			}
 
-			outFile.Write(jsonString)
-			outFile.Write([]byte("\n"))
+			if err := writer.Write(entry); err != nil {
+				return fmt.Errorf("failed to write %s: %w", mergeOutputPath, err)
+			}
+		}
+
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+
+		if mergeAttribution != "" {
+			return writeMergeAttribution(mergeAttribution, orders, attribution, user, policy)
 		}
 
 		return nil
@@ -63,8 +126,13 @@ var mergeCmd = &cobra.Command{
 }
 
 func init() {
-	mergeCmd.Flags().StringVar(&mergeOrderPath, "input", "merge_order.yml", "input order file")
+	mergeCmd.Flags().StringVar(&mergeOrderPath, "input", "merge_order.yml", "input order file (or a directory or merged jsonl file)")
 	mergeCmd.Flags().StringVar(&mergeOutputPath, "output", "merged.jsonl", "output file")
+	mergeCmd.Flags().StringVar(&mergeCollation, "collation", "", "collation order of the merged output (gojuon, codepoint, reverse, jis)")
+	mergeCmd.Flags().StringVar(&mergeProfile, "profile", "", "merge profile defined in the order file (filters entries by tag)")
+	mergeCmd.Flags().StringVar(&mergeAttribution, "attribution", "", "write an attribution and license report (Markdown) to this file")
+	mergeCmd.Flags().StringVar(&mergeUserPath, "user", "", "user dictionary (jsonl or SKK-JISYO) whose candidates take priority; okuri-ari entries of SKK-JISYO are skipped")
+	mergeCmd.Flags().StringVar(&mergeUserEncoding, "user-encoding", "auto", "encoding of an SKK-JISYO user dictionary (auto, euc-jp, utf-8)")
 	rootCmd.AddCommand(mergeCmd)
 }
 
@@ -116,6 +184,140 @@ func makeMergeOrder(path string) ([]string, error) {
 	return orderList, nil
 }
 
+// mergeProfileConfig は merge_order.yml の profiles に定義するマージの条件
+type mergeProfileConfig struct {
+	IncludeTags []string `yaml:"include_tags"`
+	ExcludeTags []string `yaml:"exclude_tags"`
+}
+
+// loadMergeProfile は path の profiles から name のプロファイルを読み込む
+func loadMergeProfile(path string, name string) (mergeProfileConfig, error) {
+	orderFile, err := os.ReadFile(path)
+	if err != nil {
+		return mergeProfileConfig{}, fmt.Errorf("failed to read order file")
+	}
+
+	var order struct {
+		Profiles map[string]mergeProfileConfig `yaml:"profiles"`
+	}
+
+	if err := yaml.Unmarshal(orderFile, &order); err != nil {
+		return mergeProfileConfig{}, fmt.Errorf("failed to read order file")
+	}
+
+	profile, ok := order.Profiles[name]
+	if !ok {
+		return mergeProfileConfig{}, fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(slices.Sorted(maps.Keys(order.Profiles)), ", "))
+	}
+
+	for _, tag := range slices.Concat(profile.IncludeTags, profile.ExcludeTags) {
+		if !slices.Contains(dictionary.Tags, tag) {
+			return mergeProfileConfig{}, fmt.Errorf("profile %q: %w %q", name, dict.ErrUnknownTag, tag)
+		}
+	}
+
+	return profile, nil
+}
+
+// writeMergeAttribution はマージ結果の出典とライセンスのレポートを path に書き出す
+// 利用者の辞書の候補は --user のパスに一致する attribution の出典で数える
+func writeMergeAttribution(path string, orders []string, attribution map[string]dict.Provenance, user []dict.Entry, policy dict.MergePolicy) error {
+	defaults := fileProvenance(attribution, mergeUserPath)
+
+	user = slices.Clone(user)
+	for i := range user {
+		user[i].Provenance = defaults.Overlay(user[i].Provenance)
+	}
+
+	counts, err := makeAttribution(user, orders, attribution, policy)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	writeAttribution(writer, counts)
+
+	return writer.Flush()
+}
+
+// loadUserDictionary は利用者の辞書やマージ済みファイルを読み込む
+//   - 拡張子が .jsonl のファイル: 辞書ファイルと同じ JSONL 形式
+//   - それ以外: SKK-JISYO 形式 (送りなしエントリのみ)、encoding が auto の時は UTF-8 でなければ EUC-JP とみなす
+//
+// skipped は読み飛ばした SKK-JISYO の送りありエントリの数
+func loadUserDictionary(path string, encoding string) (entries []dict.Entry, skipped int, err error) {
+	if strings.HasSuffix(path, ".jsonl") {
+		entries, err := readEntries(path)
+		return entries, 0, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if encoding == "auto" {
+		encoding = "euc-jp"
+		if utf8.Valid(content) {
+			encoding = "utf-8"
+		}
+	}
+
+	codec, err := findSkkservCodec(encoding)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	text, err := codec.decode(content)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+
+	entries, skipped, err = dict.ReadSkkJisyo(strings.NewReader(text))
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return entries, skipped, nil
+}
+
+// readEntries は path の辞書ファイルを全て読み込む
+func readEntries(path string) ([]dict.Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	defer file.Close()
+
+	entryReader := dict.NewReader(file)
+	entryReader.SetMaxLineSize(maxLineSize)
+
+	var entries []dict.Entry
+
+	for {
+		entry, err := entryReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
 // fileExists はファイルの有無を確認する
 // ファイルがあるときtrueを返す
 func fileExists(path string) bool {
@@ -123,50 +325,89 @@ func fileExists(path string) bool {
 	return err == nil
 }
 
-func makeMergeData(orders []string) (map[string][]string, error) {
-	outMap := make(map[string][]string)
+// mergePolicy はコマンドのオプションを反映したマージ方針を作成する
+func mergePolicy(compare func(a string, b string) int) dict.MergePolicy {
+	return dict.MergePolicy{Compare: compare, MaxLineSize: maxLineSize}
+}
+
+// makeMergeData はマージ結果をメモリ上に作成する
+// 大きな辞書を扱う時は dict.Merge で逐次処理すること
+func makeMergeData(orders []string) ([]dict.Entry, error) {
+	var entries []dict.Entry
 
-	for _, path := range orders {
-		// jsonlファイルオープン
-		file, err := os.Open(path)
+	for entry, err := range dict.Merge(dict.FileSources(orders), mergePolicy(dict.Compare)) {
 		if err != nil {
 			return nil, err
 		}
 
-		// 関数終了時にファイルクローズ呼出を強制
-		defer file.Close()
+		entries = append(entries, entry)
+	}
 
-		scanner := bufio.NewScanner(file)
+	return entries, nil
+}
 
-		for scanner.Scan() {
-			var record dictionary.Entry
+// loadDictionary は path の辞書をマージしてメモリ上に読み込む
+// path の解釈は resolveDictionaryFiles を参照
+func loadDictionary(path string) ([]dict.Entry, error) {
+	orders, err := resolveDictionaryFiles(path)
+	if err != nil {
+		return nil, err
+	}
 
-			if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
-				return nil, fmt.Errorf("parse error: %s", path)
-			}
+	return makeMergeData(orders)
+}
 
-			// 初回データはそのまま投入
-			if _, ok := outMap[record.Key]; !ok {
-				outMap[record.Key] = record.Value
-				continue
-			}
+// isOrderFile は path が merge_order.yml 形式のファイル (yml/yaml) の時 true を返す
+func isOrderFile(path string) bool {
+	return strings.HasSuffix(path, ".yml") || strings.HasSuffix(path, ".yaml")
+}
+
+// skipDenyFile は WalkJsonl で削除リスト (.deny.jsonl) を読み飛ばすフィルタ
+// 削除リストは誤った候補を列挙するため、通常の辞書ファイルとして扱うコマンドでは対象外にする
+func skipDenyFile(path string, _ string) bool {
+	return !dict.IsDenyFile(path)
+}
 
-			// ここから先は重複データ
-			outMap[record.Key] = mergeSlice(outMap[record.Key], record.Value)
+// resolveDictionaryFiles は path から辞書ファイルのリストを作成する
+//   - ディレクトリ: 配下の全 jsonl ファイル (パス順)
+//   - yml/yaml ファイル: merge_order.yml 形式のファイルリスト
+//   - それ以外: 1つの jsonl ファイル (マージ済みファイルなど)
+func resolveDictionaryFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if isOrderFile(path) {
+		orders, err := makeMergeOrder(path)
+		if err != nil {
+			return nil, fmt.Errorf("nothing order %s: %w", path, err)
 		}
+
+		return orders, nil
 	}
 
-	return outMap, nil
-}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
 
-func mergeSlice(source []string, input []string) []string {
-	for _, value := range input {
-		if slices.Contains(source, value) {
-			continue
+	var orders []string
+
+	err = filepath.WalkDir(path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() && strings.HasSuffix(d.Name(), ".jsonl") {
+			orders = append(orders, path)
 		}
 
-		source = append(source, value)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
 	}
 
-	return source
+	return orders, nil
 }
@@ -3,8 +3,11 @@ package cmd
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+
+	"golang.org/x/text/encoding/japanese"
 )
 
 func TestMergeCommand_Minimal(t *testing.T) {
@@ -68,3 +71,178 @@ func TestMergeCommand_Minimal(t *testing.T) {
 		t.Fatalf("expected third line to contain unkeyed record, got %s", lines[2])
 	}
 }
+
+func TestMergeEntries_SortedAndUnsorted(t *testing.T) {
+	d := t.TempDir()
+
+	sorted := filepath.Join(d, "sorted.jsonl")
+	unsorted := filepath.Join(d, "unsorted.jsonl")
+
+	// sorted.jsonl は逐次読み込み、unsorted.jsonl はメモリ上でソートされる
+	if err := os.WriteFile(sorted, []byte(strings.Join([]string{
+		`{"key":"あい","value":["愛","藍"]}`,
+		`{"key":"きのう","value":["機能"]}`,
+		`{"key":"きのう","value":["昨日"]}`,
+	}, "\n")+"\n"), 0o644); err != nil {
+		t.Fatalf("write sorted.jsonl: %v", err)
+	}
+
+	if err := os.WriteFile(unsorted, []byte(strings.Join([]string{
+		`{"key":"きのう","value":["帰納","機能"]}`,
+		`{"key":"あお","value":["青"]}`,
+		`{"key":"あい","value":["哀"]}`,
+	}, "\n")+"\n"), 0o644); err != nil {
+		t.Fatalf("write unsorted.jsonl: %v", err)
+	}
+
+	entries, err := makeMergeData([]string{sorted, unsorted})
+	if err != nil {
+		t.Fatalf("merge failed: %v", err)
+	}
+
+	expected := []struct {
+		key   string
+		value []string
+	}{
+		{"あい", []string{"愛", "藍", "哀"}},
+		{"あお", []string{"青"}},
+		{"きのう", []string{"機能", "昨日", "帰納"}},
+	}
+
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %d entries, got %d: %v", len(expected), len(entries), entries)
+	}
+
+	for i, e := range expected {
+		if entries[i].Key != e.key || !slices.Equal(entries[i].Value, e.value) {
+			t.Fatalf("expected %s %v at index %d, got %s %v", e.key, e.value, i, entries[i].Key, entries[i].Value)
+		}
+	}
+}
+
+func TestMergeCommand_Numeric(t *testing.T) {
+	defer func(input string, output string) { mergeOrderPath, mergeOutputPath = input, output }(mergeOrderPath, mergeOutputPath)
+
+	d := t.TempDir()
+	mergeOrderPath = filepath.Join(d, "number.jsonl")
+	mergeOutputPath = filepath.Join(d, "merged.jsonl")
+
+	writeTestFile(t, mergeOrderPath, strings.Join([]string{
+		`{"key": "#かい", "value": ["#1回", "#3回"]}`,
+		`{"key": "いち", "value": ["一"]}`,
+	}, "\n")+"\n")
+
+	if err := mergeCmd.RunE(mergeCmd, nil); err != nil {
+		t.Fatalf("merge command failed: %v", err)
+	}
+
+	out, err := os.ReadFile(mergeOutputPath)
+	if err != nil {
+		t.Fatalf("read merged.jsonl: %v", err)
+	}
+
+	// 数値変換エントリは先頭に並び、候補の #n はそのまま出力される
+	if lines := strings.Split(string(out), "\n"); lines[0] != `{"key": "#かい", "value": ["#1回", "#3回"]}` {
+		t.Fatalf("unexpected merged output: %s", out)
+	}
+}
+
+func TestMergeEntries_LongLine(t *testing.T) {
+	d := t.TempDir()
+	path := filepath.Join(d, "long.jsonl")
+
+	if err := os.WriteFile(path, []byte(longEntryLine("きのう")+"\n"+`{"key":"きのう","value":["昨日"]}`+"\n"), 0o644); err != nil {
+		t.Fatalf("write long.jsonl: %v", err)
+	}
+
+	entries, err := makeMergeData([]string{path})
+	if err != nil {
+		t.Fatalf("merge failed: %v", err)
+	}
+
+	expected := []string{"機能", "昨日"}
+	if len(entries) != 1 || !slices.Equal(entries[0].Value, expected) {
+		t.Fatalf("expected single entry %v, got %v", expected, entries)
+	}
+}
+
+func TestLoadMergeProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "merge_order.yml")
+
+	if err := os.WriteFile(path, []byte(strings.Join([]string{
+		"files:",
+		"  - \"a.jsonl\"",
+		"profiles:",
+		"  no-archaic:",
+		"    exclude_tags: [\"archaic\"]",
+		"  typo:",
+		"    include_tags: [\"archaik\"]",
+	}, "\n")+"\n"), 0o644); err != nil {
+		t.Fatalf("write merge_order.yml: %v", err)
+	}
+
+	profile, err := loadMergeProfile(path, "no-archaic")
+	if err != nil || !slices.Equal(profile.ExcludeTags, []string{"archaic"}) {
+		t.Fatalf("unexpected profile %v: %v", profile, err)
+	}
+
+	if _, err := loadMergeProfile(path, "typo"); err == nil {
+		t.Fatalf("expected unknown tag error")
+	}
+
+	if _, err := loadMergeProfile(path, "missing"); err == nil {
+		t.Fatalf("expected unknown profile error")
+	}
+}
+
+func TestLoadUserDictionary_EucJp(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".skk-jisyo")
+
+	content, err := japanese.EUCJP.NewEncoder().Bytes([]byte(";; okuri-ari entries.\nかえr /帰/\n;; okuri-nasi entries.\nきのう /昨日/\n"))
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatalf("write user dictionary: %v", err)
+	}
+
+	entries, skipped, err := loadUserDictionary(path, "auto")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(entries) != 1 || entries[0].Key != "きのう" || !slices.Equal(entries[0].Value, []string{"昨日"}) {
+		t.Fatalf("unexpected entries: %v", entries)
+	}
+
+	// 送りありエントリは読み飛ばした数を返す
+	if skipped != 1 {
+		t.Fatalf("expected 1 skipped okuri-ari entry, got %d", skipped)
+	}
+}
+
+func TestMergeCommand_OrderOptionsRequireOrderFile(t *testing.T) {
+	defer func(input string, output string, profile string, attribution string) {
+		mergeOrderPath, mergeOutputPath, mergeProfile, mergeAttribution = input, output, profile, attribution
+	}(mergeOrderPath, mergeOutputPath, mergeProfile, mergeAttribution)
+
+	d := t.TempDir()
+	mergeOrderPath = filepath.Join(d, "a.jsonl")
+	mergeOutputPath = filepath.Join(d, "merged.jsonl")
+
+	writeTestFile(t, mergeOrderPath, `{"key": "あい", "value": ["愛"]}`+"\n")
+
+	for _, options := range [][2]string{{"no-archaic", ""}, {"", filepath.Join(d, "a.md")}} {
+		mergeProfile, mergeAttribution = options[0], options[1]
+
+		if err := mergeCmd.RunE(mergeCmd, nil); err == nil {
+			t.Fatalf("expected an error for %v with a jsonl input", options)
+		}
+
+		// エラーの時はマージ結果を書き出さない
+		if fileExists(mergeOutputPath) {
+			t.Fatalf("merged output should not be created for %v", options)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"siguma0013/reskk-dictionary/internal/dictionary"
+	"siguma0013/reskk-dictionary/internal/utility"
+	dict "siguma0013/reskk-dictionary/pkg/dictionary"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+)
+
+// オプション
+var (
+	readingTablePath string
+	isReadingStrict  bool
+)
+
+var (
+	rendakuKana = dictionary.Rendaku()
+	sokuonKana  = dictionary.Sokuon()
+)
+
+var readingCheckCmd = &cobra.Command{
+	Use:          "reading",
+	Short:        "読みと候補の漢字の読みが一致するかチェックするコマンド",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filePath := args[0]
+
+		table, err := loadReadingTable(readingTablePath)
+		if err != nil {
+			return err
+		}
+
+		results := utility.WalkJsonl(filePath, skipDenyFile, func(path string, file io.Reader) []error {
+			return checkReading(file, table, isReadingStrict)
+		})
+
+		if utility.PrintResults(results) {
+			return fmt.Errorf("inconsistent reading found")
+		}
+
+		fmt.Println("All JSONL files are valid")
+
+		return nil
+	},
+}
+
+func init() {
+	readingCheckCmd.Flags().StringVar(&readingTablePath, "readings", "data/kanji_readings.tsv", "kanji reading table")
+	readingCheckCmd.Flags().BoolVar(&isReadingStrict, "strict", false, "report kanji missing from the reading table")
+	rootCmd.AddCommand(readingCheckCmd)
+}
+
+// readingTable は漢字 (または熟字訓などの語) → 読みの一覧
+type readingTable map[string][]string
+
+// readingOption は候補の先頭 size 文字に当てはまる読み
+type readingOption struct {
+	size    int
+	reading string
+}
+
+// loadReadingTable は漢字の読み表を読み込む
+func loadReadingTable(path string) (readingTable, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reading table: %w", err)
+	}
+
+	defer file.Close()
+
+	return parseReadingTable(file)
+}
+
+// parseReadingTable は "漢字<TAB>読み 読み ..." 形式の読み表をパースする
+// 漢字の代わりに1文字ずつ読めない語 (一日 → ついたち など) も指定できる
+// 空行と # で始まる行は無視する
+func parseReadingTable(reader io.Reader) (readingTable, error) {
+	scanner := newLineReader(reader)
+	table := make(readingTable)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		kanji, readings, ok := strings.Cut(line, "\t")
+
+		if !ok || kanji == "" {
+			return nil, fmt.Errorf("reading table line %d: expected \"kanji<TAB>readings\"", scanner.Line())
+		}
+
+		table[kanji] = append(table[kanji], strings.Fields(readings)...)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading table: %w", err)
+	}
+
+	return table, nil
+}
+
+// readingVariants は reading の連濁・促音化した形を含む読みの一覧を返す
+//   - withRendaku: 語頭の清音を濁音・半濁音にした形を含める
+//   - withSokuon: 語末の つ ち く き を っ にした形を含める
+func readingVariants(reading string, withRendaku bool, withSokuon bool) []string {
+	variants := []string{reading}
+	runes := []rune(reading)
+
+	if withRendaku {
+		for _, voiced := range rendakuKana[runes[0]] {
+			variants = append(variants, string(voiced)+string(runes[1:]))
+		}
+	}
+
+	if withSokuon && len(runes) >= 2 && sokuonKana[runes[len(runes)-1]] {
+		for _, variant := range variants {
+			variantRunes := []rune(variant)
+			variants = append(variants, string(variantRunes[:len(variantRunes)-1])+"っ")
+		}
+	}
+
+	return variants
+}
+
+// canRead は候補 candidate を読み key で読めるか判定する
+// 読み表に無い漢字や仮名以外の文字を含む時は判定できないため、その文字を unknown に返す
+func (t readingTable) canRead(candidate string, key string) (ok bool, unknown rune) {
+	chars := []rune(candidate)
+	options := make([][]readingOption, len(chars))
+	covered := make([]bool, len(chars))
+
+	add := func(i int, size int, readings []string) {
+		for _, reading := range readings {
+			for _, variant := range readingVariants(reading, i > 0, i+size < len(chars)) {
+				options[i] = append(options[i], readingOption{size: size, reading: variant})
+			}
+		}
+
+		for j := i; j < i+size; j++ {
+			covered[j] = true
+		}
+	}
+
+	for i, r := range chars {
+		switch {
+		case r >= 'ぁ' && r <= 'ゖ', r == 'ー':
+			options[i] = append(options[i], readingOption{size: 1, reading: string(r)})
+			covered[i] = true
+		case r >= 'ァ' && r <= 'ヶ':
+			options[i] = append(options[i], readingOption{size: 1, reading: string(r - ('ァ' - 'ぁ'))})
+			covered[i] = true
+		case r == '々' && i > 0 && unicode.Is(unicode.Han, chars[i-1]) && len(t[string(chars[i-1])]) > 0:
+			add(i, 1, t[string(chars[i-1])])
+		case unicode.Is(unicode.Han, r) && len(t[string(r)]) > 0:
+			add(i, 1, t[string(r)])
+		}
+
+		// 複数文字の語
+		for size := 2; i+size <= len(chars); size++ {
+			if readings := t[string(chars[i:i+size])]; len(readings) > 0 {
+				add(i, size, readings)
+			}
+		}
+	}
+
+	for i, r := range chars {
+		if !covered[i] {
+			return false, r
+		}
+	}
+
+	// memo[i][pos] は chars[i:] で key[pos:] を読めるか (0: 未計算, 1: 読める, 2: 読めない)
+	memo := make([][]int8, len(chars)+1)
+	for i := range memo {
+		memo[i] = make([]int8, len(key)+1)
+	}
+
+	var solve func(i int, pos int) bool
+
+	solve = func(i int, pos int) bool {
+		if i == len(chars) {
+			return pos == len(key)
+		}
+
+		if memo[i][pos] != 0 {
+			return memo[i][pos] == 1
+		}
+
+		result := false
+
+		for _, option := range options[i] {
+			if strings.HasPrefix(key[pos:], option.reading) && solve(i+option.size, pos+len(option.reading)) {
+				result = true
+				break
+			}
+		}
+
+		memo[i][pos] = 2
+		if result {
+			memo[i][pos] = 1
+		}
+
+		return result
+	}
+
+	return solve(0, 0), 0
+}
+
+// checkReading は読みと候補の一致をチェックする
+// strict の時は読み表に無い文字も報告する
+func checkReading(reader io.Reader, table readingTable, strict bool) []error {
+	scanner := newLineReader(reader)
+
+	var results []error
+
+	// 1行づつ繰り返し処理
+	for scanner.Scan() {
+		lineCount := scanner.Line()
+
+		var record dict.Entry
+
+		// パース
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			results = append(results, fmt.Errorf("parse error: %d", lineCount))
+			continue
+		}
+
+		for _, value := range record.Value {
+			ok, unknown := table.canRead(value, record.Key)
+
+			if unknown != 0 {
+				if strict {
+					results = append(results, fmt.Errorf("line %d: %q in %q is not in the reading table", lineCount, string(unknown), value))
+				}
+				continue
+			}
+
+			if !ok {
+				results = append(results, fmt.Errorf("line %d: %q cannot be read as %q", lineCount, value, record.Key))
+			}
+		}
+	}
+
+	if scannerError := scanner.Err(); scannerError != nil {
+		results = append(results, fmt.Errorf("scanner error: %w", scannerError))
+	}
+
+	return results
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckReading(t *testing.T) {
+	table, err := parseReadingTable(strings.NewReader(strings.Join([]string{
+		"# comment",
+		"暗\tあん くら",
+		"号\tごう",
+		"必\tひつ かなら",
+		"須\tす しゅ",
+		"桜\tおう さくら",
+		"山\tさん やま",
+		"人\tじん にん ひと",
+		"日\tにち ひ か",
+		"十\tじゅう じっ じゅっ",
+		"四日\tよっか",
+	}, "\n")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		jsonl  string
+		errors int
+	}{
+		{"plain", `{"key": "あんごう", "value": ["暗号"]}`, 0},
+		{"typo", `{"key": "あんご", "value": ["暗号"]}`, 1},
+		{"sokuon", `{"key": "ひっす", "value": ["必須"]}`, 0},
+		{"rendaku", `{"key": "やまざくら", "value": ["山桜"]}`, 0},
+		{"no rendaku at head", `{"key": "ざくら", "value": ["桜"]}`, 1},
+		{"odoriji", `{"key": "ひとびと", "value": ["人々"]}`, 0},
+		{"kana", `{"key": "くらやみ", "value": ["暗ヤミ"]}`, 0},
+		{"unknown kanji", `{"key": "きのう", "value": ["機能"]}`, 0},
+		{"word", `{"key": "よっか", "value": ["四日"]}`, 0},
+		{"word in compound", `{"key": "じゅうよっか", "value": ["十四日"]}`, 0},
+		{"one of values", `{"key": "あんごう", "value": ["暗号", "暗合"]}`, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := checkReading(strings.NewReader(test.jsonl), table, false)
+			if len(errs) != test.errors {
+				t.Fatalf("expected %d errors, got %v", test.errors, errs)
+			}
+		})
+	}
+
+	if errs := checkReading(strings.NewReader(`{"key": "きのう", "value": ["機能"]}`), table, true); len(errs) != 1 {
+		t.Fatalf("expected unknown kanji to be reported in strict mode, got %v", errs)
+	}
+}
+
+func TestReadingCommand_SkipsDenyFile(t *testing.T) {
+	defer func(readings string) { readingTablePath = readings }(readingTablePath)
+
+	dir := t.TempDir()
+	readingTablePath = filepath.Join(dir, "readings.tsv")
+
+	jsonl := filepath.Join(dir, "jsonl")
+
+	if err := os.MkdirAll(jsonl, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	writeTestFile(t, readingTablePath, "暗\tあん\n号\tごう\n")
+	writeTestFile(t, filepath.Join(jsonl, "a.jsonl"), `{"key": "あんごう", "value": ["暗号"]}`+"\n")
+
+	// 削除リストは読みの合わない候補を列挙するためチェックしない
+	writeTestFile(t, filepath.Join(jsonl, "bad.deny.jsonl"), `{"key": "あんご", "value": ["暗号"]}`+"\n")
+
+	if err := readingCheckCmd.RunE(readingCheckCmd, []string{jsonl}); err != nil {
+		t.Fatalf("reading check failed: %v", err)
+	}
+}
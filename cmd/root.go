@@ -4,11 +4,16 @@ Copyright © 2026 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"io"
 	"os"
+	"siguma0013/reskk-dictionary/internal/utility"
 
 	"github.com/spf13/cobra"
 )
 
+// maxLineSize は辞書ファイル1行の最大バイト数、0 は無制限
+var maxLineSize int
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "reskk-dictionary",
@@ -38,9 +43,18 @@ func init() {
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 
-	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.reskk-dictionary.yaml)")
+	rootCmd.PersistentFlags().StringVar(&directoryConfigPath, "config", "directory_config.yml", "directory config file")
+	rootCmd.PersistentFlags().IntVar(&maxLineSize, "max-line-size", 0, "maximum line size in bytes (0: unlimited)")
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
+
+// newLineReader は --max-line-size を反映した行単位の読み込みを作成する
+func newLineReader(reader io.Reader) *utility.LineReader {
+	lineReader := utility.NewLineReader(reader)
+	lineReader.MaxSize = maxLineSize
+
+	return lineReader
+}
@@ -0,0 +1,330 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	dict "siguma0013/reskk-dictionary/pkg/dictionary"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultPrefixLimit は前方一致検索で返す既定の最大件数
+const defaultPrefixLimit = 100
+
+// オプション
+var (
+	serveAddr      string
+	serveOrderPath string
+	serveWatchPath string
+	serveInterval  time.Duration
+)
+
+var serveCmd = &cobra.Command{
+	Use:          "serve",
+	Short:        "マージした辞書を HTTP で検索できるローカルサーバーを起動するコマンド",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		store, err := startServeStore(ctx, serveOrderPath, serveWatchPath, serveInterval)
+		if err != nil {
+			return err
+		}
+
+		server := &http.Server{Addr: serveAddr, Handler: newServeHandler(store)}
+
+		go func() {
+			<-ctx.Done()
+			server.Shutdown(context.Background())
+		}()
+
+		log.Printf("listening on %s", serveAddr)
+
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			return err
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "127.0.0.1:8080", "listen address")
+	serveCmd.Flags().StringVar(&serveOrderPath, "input", "merge_order.yml", "input order file")
+	serveCmd.Flags().StringVar(&serveWatchPath, "watch", "jsonl", "directory to watch for hot reload")
+	serveCmd.Flags().DurationVar(&serveInterval, "interval", time.Second, "polling interval for hot reload")
+	rootCmd.AddCommand(serveCmd)
+}
+
+// serveStats は /stats で返す統計情報
+type serveStats struct {
+	Entries    int       `json:"entries"`
+	Candidates int       `json:"candidates"`
+	Files      int       `json:"files"`
+	LoadedAt   time.Time `json:"loaded_at"`
+}
+
+// serveIndex は検索用に加工したマージ済み辞書
+type serveIndex struct {
+	entries []dict.Entry
+	byKey   map[string]int   // 読み → entries の位置
+	byWord  map[string][]int // 候補 → entries の位置
+	sorted  []int            // 読みのバイト順に並べた entries の位置 (前方一致用)
+	stats   serveStats
+}
+
+// newServeIndex はマージ済みエントリから検索用の索引を作成する
+func newServeIndex(entries []dict.Entry) *serveIndex {
+	index := &serveIndex{
+		entries: entries,
+		byKey:   make(map[string]int, len(entries)),
+		byWord:  make(map[string][]int),
+		stats:   serveStats{LoadedAt: time.Now()},
+	}
+
+	for i, entry := range entries {
+		// キーの無いレコードは検索対象外
+		if entry.Key == "" {
+			continue
+		}
+
+		index.byKey[entry.Key] = i
+		index.sorted = append(index.sorted, i)
+		index.stats.Entries++
+		index.stats.Candidates += len(entry.Value)
+
+		for _, value := range entry.Value {
+			index.byWord[value] = append(index.byWord[value], i)
+		}
+	}
+
+	slices.SortFunc(index.sorted, func(a int, b int) int {
+		return strings.Compare(entries[a].Key, entries[b].Key)
+	})
+
+	return index
+}
+
+// lookup は読みに完全一致するエントリを返す
+func (x *serveIndex) lookup(key string) (dict.Entry, bool) {
+	i, ok := x.byKey[key]
+	if !ok {
+		return dict.Entry{}, false
+	}
+
+	return x.entries[i], true
+}
+
+// prefix は読みが key から始まるエントリを最大 limit 件返す
+func (x *serveIndex) prefix(key string, limit int) []dict.Entry {
+	start, _ := slices.BinarySearchFunc(x.sorted, key, func(i int, target string) int {
+		return strings.Compare(x.entries[i].Key, target)
+	})
+
+	entries := []dict.Entry{}
+
+	for _, i := range x.sorted[start:] {
+		if !strings.HasPrefix(x.entries[i].Key, key) || len(entries) >= limit {
+			break
+		}
+
+		entries = append(entries, x.entries[i])
+	}
+
+	return entries
+}
+
+// reverse は候補に word を含むエントリを返す
+func (x *serveIndex) reverse(word string) []dict.Entry {
+	entries := []dict.Entry{}
+
+	for _, i := range x.byWord[word] {
+		entries = append(entries, x.entries[i])
+	}
+
+	return entries
+}
+
+// serveStore は再読み込みで差し替えられる serveIndex の入れ物
+type serveStore struct {
+	mutex sync.RWMutex
+	index *serveIndex
+}
+
+// current は現在の索引を返す
+func (s *serveStore) current() *serveIndex {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.index
+}
+
+// set は索引を差し替える
+func (s *serveStore) set(index *serveIndex) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.index = index
+}
+
+// reload は merge_order.yml から辞書を読み込み直す
+// 失敗した時は現在の索引をそのまま使い続ける
+func (s *serveStore) reload(orderPath string) error {
+	orders, err := makeMergeOrder(orderPath)
+	if err != nil {
+		return fmt.Errorf("nothing order %s: %w", orderPath, err)
+	}
+
+	entries, err := makeMergeData(orders)
+	if err != nil {
+		return fmt.Errorf("missing merge data: %w", err)
+	}
+
+	index := newServeIndex(entries)
+	index.stats.Files = len(orders)
+
+	s.set(index)
+
+	return nil
+}
+
+// startServeStore は orderPath から辞書を読み込んだ serveStore を作成する
+// serve と skkserv で共通の処理で、ctx が終了するまで watchPath と orderPath の変更を監視して再読み込みする
+func startServeStore(ctx context.Context, orderPath string, watchPath string, interval time.Duration) (*serveStore, error) {
+	store := &serveStore{}
+
+	// 読み込み前の状態と比べ、読み込み中の変更も再読み込みの対象にする
+	paths := []string{watchPath, orderPath}
+	previous := treeSignature(paths)
+
+	if err := store.reload(orderPath); err != nil {
+		return nil, err
+	}
+
+	go watchTree(ctx, paths, previous, interval, func() {
+		if err := store.reload(orderPath); err != nil {
+			log.Printf("reload failed: %v", err)
+			return
+		}
+
+		log.Printf("reloaded %d entries", store.current().stats.Entries)
+	})
+
+	return store, nil
+}
+
+// newServeHandler は検索 API のハンドラーを作成する
+func newServeHandler(store *serveStore) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /lookup", func(w http.ResponseWriter, r *http.Request) {
+		entry, ok := store.current().lookup(r.URL.Query().Get("key"))
+		if !ok {
+			writeJson(w, http.StatusNotFound, map[string]string{"error": "not found"})
+			return
+		}
+
+		writeJson(w, http.StatusOK, entry)
+	})
+
+	mux.HandleFunc("GET /prefix", func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Query().Get("key")
+		if key == "" {
+			writeJson(w, http.StatusBadRequest, map[string]string{"error": "key is required"})
+			return
+		}
+
+		limit := defaultPrefixLimit
+		if value := r.URL.Query().Get("limit"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed <= 0 {
+				writeJson(w, http.StatusBadRequest, map[string]string{"error": "invalid limit"})
+				return
+			}
+
+			limit = parsed
+		}
+
+		writeJson(w, http.StatusOK, store.current().prefix(key, limit))
+	})
+
+	mux.HandleFunc("GET /reverse", func(w http.ResponseWriter, r *http.Request) {
+		writeJson(w, http.StatusOK, store.current().reverse(r.URL.Query().Get("word")))
+	})
+
+	mux.HandleFunc("GET /stats", func(w http.ResponseWriter, r *http.Request) {
+		writeJson(w, http.StatusOK, store.current().stats)
+	})
+
+	return mux
+}
+
+// writeJson はレスポンスを JSON で書き出す
+func writeJson(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(body)
+}
+
+// watchTree は paths 以下のファイルを interval ごとに確認し、previous (treeSignature) から変更があれば onChange を呼び出す
+func watchTree(ctx context.Context, paths []string, previous string, interval time.Duration, onChange func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			signature := treeSignature(paths)
+
+			if signature == previous {
+				continue
+			}
+
+			previous = signature
+			onChange()
+		}
+	}
+}
+
+// treeSignature は paths 以下のファイルのパス、サイズ、更新日時をまとめた文字列を返す
+func treeSignature(paths []string) string {
+	var builder strings.Builder
+
+	for _, root := range paths {
+		filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return nil
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+
+			fmt.Fprintf(&builder, "%s\t%d\t%d\n", path, info.Size(), info.ModTime().UnixNano())
+
+			return nil
+		})
+	}
+
+	return builder.String()
+}
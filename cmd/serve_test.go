@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	dict "siguma0013/reskk-dictionary/pkg/dictionary"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestServeHandler(t *testing.T) {
+	store := &serveStore{}
+	store.set(newServeIndex([]dict.Entry{
+		{Key: "きのう", Value: []string{"機能", "昨日"}},
+		{Key: "きのこ", Value: []string{"茸"}},
+		{Key: "さくじつ", Value: []string{"昨日"}},
+	}))
+
+	server := httptest.NewServer(newServeHandler(store))
+	defer server.Close()
+
+	get := func(path string, body any) int {
+		response, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+
+		defer response.Body.Close()
+
+		if body != nil {
+			if err := json.NewDecoder(response.Body).Decode(body); err != nil {
+				t.Fatalf("decode %s: %v", path, err)
+			}
+		}
+
+		return response.StatusCode
+	}
+
+	var entry dict.Entry
+	if status := get("/lookup?key=きのう", &entry); status != http.StatusOK || len(entry.Value) != 2 {
+		t.Fatalf("lookup: unexpected %d %v", status, entry)
+	}
+
+	if status := get("/lookup?key=なし", nil); status != http.StatusNotFound {
+		t.Fatalf("lookup: expected 404, got %d", status)
+	}
+
+	var entries []dict.Entry
+	if status := get("/prefix?key=きの", &entries); status != http.StatusOK || len(entries) != 2 {
+		t.Fatalf("prefix: unexpected %d %v", status, entries)
+	}
+
+	if status := get("/prefix?key=きの&limit=1", &entries); status != http.StatusOK || len(entries) != 1 {
+		t.Fatalf("prefix limit: unexpected %d %v", status, entries)
+	}
+
+	if status := get("/reverse?word=昨日", &entries); status != http.StatusOK || len(entries) != 2 {
+		t.Fatalf("reverse: unexpected %d %v", status, entries)
+	}
+
+	var stats serveStats
+	if status := get("/stats", &stats); status != http.StatusOK || stats.Entries != 3 || stats.Candidates != 4 {
+		t.Fatalf("stats: unexpected %d %v", status, stats)
+	}
+}
+
+func TestTreeSignature(t *testing.T) {
+	d := t.TempDir()
+	path := filepath.Join(d, "a.jsonl")
+
+	if err := os.WriteFile(path, []byte("{}\n"), 0o644); err != nil {
+		t.Fatalf("write a.jsonl: %v", err)
+	}
+
+	before := treeSignature([]string{d})
+
+	if err := os.WriteFile(path, []byte("{}\n{}\n"), 0o644); err != nil {
+		t.Fatalf("write a.jsonl: %v", err)
+	}
+
+	if treeSignature([]string{d}) == before {
+		t.Fatalf("expected signature to change after editing a file")
+	}
+}
+
+func TestStartServeStore(t *testing.T) {
+	d := t.TempDir()
+	path := filepath.Join(d, "a.jsonl")
+
+	order := filepath.Join(d, "merge_order.yml")
+
+	writeTestFile(t, path, `{"key": "きのう", "value": ["機能"]}`+"\n")
+	writeTestFile(t, order, "files:\n  - "+strconv.Quote(filepath.ToSlash(path))+"\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store, err := startServeStore(ctx, order, d, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("startServeStore: %v", err)
+	}
+
+	if store.current().stats.Entries != 1 {
+		t.Fatalf("unexpected stats: %v", store.current().stats)
+	}
+
+	// 監視しているファイルを編集すると再読み込みされる
+	writeTestFile(t, path, `{"key": "きのう", "value": ["機能"]}`+"\n"+`{"key": "きのこ", "value": ["茸"]}`+"\n")
+
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if _, ok := store.current().lookup("きのこ"); ok {
+			return
+		}
+	}
+
+	t.Fatalf("store was not reloaded after editing %s", path)
+}
@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/text/encoding/japanese"
+)
+
+// skkservVersion は skkserv プロトコルの "2" リクエストに返すバージョン文字列
+const skkservVersion = "reskk-dictionary.0.1"
+
+// オプション
+var (
+	skkservAddr      string
+	skkservOrderPath string
+	skkservWatchPath string
+	skkservEncoding  string
+	skkservInterval  time.Duration
+)
+
+var skkservCmd = &cobra.Command{
+	Use:          "skkserv",
+	Short:        "マージした辞書を skkserv プロトコルで提供するサーバーを起動するコマンド",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		codec, err := findSkkservCodec(skkservEncoding)
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		store, err := startServeStore(ctx, skkservOrderPath, skkservWatchPath, skkservInterval)
+		if err != nil {
+			return err
+		}
+
+		listener, err := net.Listen("tcp", skkservAddr)
+		if err != nil {
+			return err
+		}
+
+		go func() {
+			<-ctx.Done()
+			listener.Close()
+		}()
+
+		log.Printf("listening on %s (%s)", skkservAddr, skkservEncoding)
+
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+
+				return err
+			}
+
+			go func() {
+				defer conn.Close()
+
+				if err := handleSkkserv(conn, store, codec, conn.LocalAddr().String()); err != nil {
+					log.Printf("%s: %v", conn.RemoteAddr(), err)
+				}
+			}()
+		}
+	},
+}
+
+func init() {
+	skkservCmd.Flags().StringVar(&skkservAddr, "addr", "127.0.0.1:1178", "listen address")
+	skkservCmd.Flags().StringVar(&skkservOrderPath, "input", "merge_order.yml", "input order file")
+	skkservCmd.Flags().StringVar(&skkservWatchPath, "watch", "jsonl", "directory to watch for hot reload")
+	skkservCmd.Flags().StringVar(&skkservEncoding, "encoding", "euc-jp", "protocol encoding (euc-jp, utf-8)")
+	skkservCmd.Flags().DurationVar(&skkservInterval, "interval", time.Second, "polling interval for hot reload")
+	rootCmd.AddCommand(skkservCmd)
+}
+
+// skkservCodec は通信路の文字コードと辞書の UTF-8 を相互に変換する
+type skkservCodec struct {
+	decode func(data []byte) (string, error)
+	encode func(text string) ([]byte, error)
+}
+
+// findSkkservCodec は文字コード名から変換処理を取得する
+func findSkkservCodec(name string) (skkservCodec, error) {
+	switch strings.ToLower(name) {
+	case "euc-jp", "eucjp":
+		return skkservCodec{
+			decode: func(data []byte) (string, error) {
+				decoded, err := japanese.EUCJP.NewDecoder().Bytes(data)
+				return string(decoded), err
+			},
+			encode: func(text string) ([]byte, error) {
+				return japanese.EUCJP.NewEncoder().Bytes([]byte(text))
+			},
+		}, nil
+	case "utf-8", "utf8":
+		return skkservCodec{
+			decode: func(data []byte) (string, error) {
+				if !utf8.Valid(data) {
+					return "", fmt.Errorf("invalid utf-8 request")
+				}
+
+				return string(data), nil
+			},
+			encode: func(text string) ([]byte, error) {
+				return []byte(text), nil
+			},
+		}, nil
+	}
+
+	return skkservCodec{}, fmt.Errorf("unknown encoding %q (available: euc-jp, utf-8)", name)
+}
+
+// handleSkkserv は1接続分の skkserv プロトコルを処理する
+//   - "0": 切断
+//   - "1よみ ": 変換候補 "1/候補/.../\n"、見つからない時は "4よみ \n"
+//   - "2": バージョン
+//   - "3": ホスト名とアドレス
+//   - "4よみ ": 読みの補完 "1/よみ/.../\n"、見つからない時は "4よみ \n"
+func handleSkkserv(conn io.ReadWriter, store *serveStore, codec skkservCodec, address string) error {
+	reader := bufio.NewReader(conn)
+
+	for {
+		command, err := reader.ReadByte()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		var response string
+
+		switch command {
+		case '0':
+			return nil
+		case '1', '4':
+			request, err := reader.ReadBytes(' ')
+			if err != nil {
+				return err
+			}
+
+			key, err := codec.decode(request[:len(request)-1])
+			if err != nil {
+				response = "4 \n"
+				break
+			}
+
+			response = skkservResponse(command, key, store.current(), codec)
+		case '2':
+			response = skkservVersion + " "
+		case '3':
+			hostname, _ := os.Hostname()
+			response = hostname + ":" + address + ": "
+		default:
+			// リクエスト間の改行などは読み飛ばす
+			continue
+		}
+
+		encoded, err := codec.encode(response)
+		if err != nil {
+			encoded = []byte("4 \n")
+		}
+
+		if _, err := conn.Write(encoded); err != nil {
+			return err
+		}
+	}
+}
+
+// skkservResponse は変換 (1) と補完 (4) リクエストへの応答を作成する
+// 通信路の文字コードで表せない候補は除外する
+func skkservResponse(command byte, key string, index *serveIndex, codec skkservCodec) string {
+	var candidates []string
+
+	if command == '1' {
+		if entry, ok := index.lookup(key); ok {
+			candidates = entry.Value
+		}
+	} else {
+		for _, entry := range index.prefix(key, defaultPrefixLimit) {
+			candidates = append(candidates, entry.Key)
+		}
+	}
+
+	var builder strings.Builder
+
+	for _, candidate := range candidates {
+		if _, err := codec.encode(candidate); err != nil {
+			continue
+		}
+
+		builder.WriteString("/" + escapeSkkCandidate(candidate))
+	}
+
+	if builder.Len() == 0 {
+		return "4" + key + " \n"
+	}
+
+	return "1" + builder.String() + "/\n"
+}
+
+// escapeSkkCandidate は SKK 辞書の区切り文字 (/ と ;) を含む候補を concat 形式に変換する
+func escapeSkkCandidate(candidate string) string {
+	if !strings.ContainsAny(candidate, "/;") {
+		return candidate
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "/", `\057`, ";", `\073`)
+
+	return `(concat "` + replacer.Replace(candidate) + `")`
+}
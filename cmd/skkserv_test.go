@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	dict "siguma0013/reskk-dictionary/pkg/dictionary"
+	"strings"
+	"testing"
+)
+
+func TestHandleSkkserv(t *testing.T) {
+	store := &serveStore{}
+	store.set(newServeIndex([]dict.Entry{
+		{Key: "きのう", Value: []string{"機能", "昨日"}},
+		{Key: "きのこ", Value: []string{"茸"}},
+		{Key: "えいち", Value: []string{"h/w;x", "😀"}},
+	}))
+
+	tests := []struct {
+		encoding string
+		request  string
+		expected string
+	}{
+		{"utf-8", "1きのう ", "1/機能/昨日/\n"},
+		{"utf-8", "1なし ", "4なし \n"},
+		{"utf-8", "4きの \n", "1/きのう/きのこ/\n"},
+		{"utf-8", "2", skkservVersion + " "},
+		{"utf-8", "1えいち 0", "1/(concat \"h\\057w\\073x\")/😀/\n"},
+		{"euc-jp", "1えいち ", "1/(concat \"h\\057w\\073x\")/\n"},
+		{"euc-jp", "1きのう 1きのこ ", "1/機能/昨日/\n1/茸/\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.encoding+"/"+test.request, func(t *testing.T) {
+			codec, err := findSkkservCodec(test.encoding)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			request, err := codec.encode(test.request)
+			if err != nil {
+				t.Fatalf("encode request: %v", err)
+			}
+
+			var response bytes.Buffer
+			conn := struct {
+				io.Reader
+				io.Writer
+			}{bytes.NewReader(request), &response}
+
+			if err := handleSkkserv(conn, store, codec, "127.0.0.1:1178"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			decoded, err := codec.decode(response.Bytes())
+			if err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+
+			if decoded != test.expected {
+				t.Fatalf("expected %q, got %q", test.expected, decoded)
+			}
+		})
+	}
+}
+
+func TestHandleSkkserv_Hostname(t *testing.T) {
+	codec, _ := findSkkservCodec("utf-8")
+
+	var response bytes.Buffer
+	conn := struct {
+		io.Reader
+		io.Writer
+	}{strings.NewReader("3"), &response}
+
+	if err := handleSkkserv(conn, &serveStore{}, codec, "127.0.0.1:1178"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.HasSuffix(response.String(), ":127.0.0.1:1178: ") {
+		t.Fatalf("unexpected hostname response %q", response.String())
+	}
+}
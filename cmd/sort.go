@@ -1,24 +1,25 @@
 package cmd
 
 import (
-	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
-	"siguma0013/reskk-dictionary/internal/dictionary"
 	"siguma0013/reskk-dictionary/internal/utility"
-	"sort"
-	"strings"
+	dict "siguma0013/reskk-dictionary/pkg/dictionary"
 
 	"github.com/spf13/cobra"
 )
 
 // sortCmd represents the sort command
 var (
-	isSortCi  bool
-	isSortFix bool
+	isSortCi       bool
+	isSortFix      bool
+	isSortDedupe   bool
+	isSortByWeight bool
+	sortCollation  string
 )
 
 var sortCmd = &cobra.Command{
@@ -29,13 +30,40 @@ var sortCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		filePath := args[0]
 
+		// 値の正規化は修正時のみ有効
+		if (isSortDedupe || isSortByWeight) && !isSortFix {
+			return fmt.Errorf("--dedupe and --by-weight require --fix")
+		}
+
+		// 照合順序の指定ミスはファイル処理前に検出する
+		if _, err := dict.Collation(sortCollation); err != nil {
+			return err
+		}
+
+		configs, err := loadDirectoryConfig(directoryConfigPath)
+		if err != nil {
+			return err
+		}
+
 		var results []utility.FileResult
 
 		if isSortFix {
-			results = utility.WalkJsonl(filePath, nil, sortJsonl)
+			results = utility.WalkJsonl(filePath, nil, func(path string, file io.Reader) []error {
+				compare, err := resolveCollation(configs, path)
+				if err != nil {
+					return []error{err}
+				}
+
+				return sortJsonl(path, file, compare)
+			})
 		} else {
 			results = utility.WalkJsonl(filePath, sortFilter, func(path string, file io.Reader) []error {
-				return checkSorted(file)
+				compare, err := resolveCollation(configs, path)
+				if err != nil {
+					return []error{err}
+				}
+
+				return checkSortedBy(file, compare)
 			})
 		}
 
@@ -52,6 +80,9 @@ var sortCmd = &cobra.Command{
 func init() {
 	sortCmd.Flags().BoolVar(&isSortCi, "ci", false, "use ci")
 	sortCmd.Flags().BoolVar(&isSortFix, "fix", false, "Fix files by sorting keys in place")
+	sortCmd.Flags().StringVar(&sortCollation, "collation", "", "collation order (gojuon, codepoint, reverse, jis); overrides directory config")
+	sortCmd.Flags().BoolVar(&isSortDedupe, "dedupe", false, "Merge entries sharing a key and remove duplicate values (with --fix)")
+	sortCmd.Flags().BoolVar(&isSortByWeight, "by-weight", false, "Order values by their weight, highest first (with --fix)")
 	rootCmd.AddCommand(sortCmd)
 }
 
@@ -65,14 +96,29 @@ func sortFilter(path string, _ string) bool {
 	return filepath.Base(path) != "number.jsonl"
 }
 
-func sortJsonl(path string, reader io.Reader) []error {
+// resolveCollation は path に適用する照合順序を決定する
+// 優先順位は --collation フラグ → ディレクトリ設定 → 既定の五十音順
+func resolveCollation(configs map[string]directoryConfig, path string) (func(a string, b string) int, error) {
+	if sortCollation != "" {
+		return dict.Collation(sortCollation)
+	}
+
+	config, _ := lookupDirectoryConfig(configs, path)
+
+	return dict.Collation(config.Collation)
+}
+
+func sortJsonl(path string, reader io.Reader, compare func(a string, b string) int) []error {
 	// ソート済みデータの作成
-	sorted, err := sortData(reader, dictionary.SortOrder())
+	sorted, err := sortDataBy(reader, compare)
 
 	if err != nil {
 		return []error{err}
 	}
 
+	// 値の正規化
+	sorted = dict.Normalize(sorted, dict.NormalizeOptions{Dedupe: isSortDedupe, ByWeight: isSortByWeight})
+
 	// 出力ディレクトリの特定
 	outputDir := filepath.Dir(path)
 
@@ -85,12 +131,18 @@ func sortJsonl(path string, reader io.Reader) []error {
 	// 関数終了時に一時ファイルを削除
 	defer os.Remove(tmp.Name())
 
+	writer := dict.NewWriter(tmp)
+
 	for _, e := range sorted {
-		b, _ := json.Marshal(e)
-		tmpLine := strings.ReplaceAll(string(b), ":\"", ": \"")
-		tmpLine2 := strings.ReplaceAll(tmpLine, ":[", ": [")
-		okLine := strings.ReplaceAll(tmpLine2, ",\"", ", \"")
-		fmt.Fprintln(tmp, okLine)
+		if err := writer.Write(e); err != nil {
+			tmp.Close()
+			return []error{err}
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		return []error{err}
 	}
 
 	// ファイル置換のために書き込みが完全終了してから処理移行
@@ -104,21 +156,23 @@ func sortJsonl(path string, reader io.Reader) []error {
 	return nil
 }
 
-// checkSorted checks that each successive 'key' is in non-decreasing order
+// checkSorted checks that each successive 'key' is in non-decreasing gojuon order
 func checkSorted(reader io.Reader) []error {
-	scanner := bufio.NewScanner(reader)
-	lineCount := 0
+	return checkSortedBy(reader, dict.Compare)
+}
+
+// checkSortedBy checks that each successive 'key' is in non-decreasing order according to compare
+func checkSortedBy(reader io.Reader, compare func(a string, b string) int) []error {
+	scanner := newLineReader(reader)
 
 	var errors []error
 	var prevKey string
 
-	var orderMap = dictionary.SortOrder()
-
 	// 1行づつ繰り返し処理
 	for scanner.Scan() {
-		lineCount++
+		lineCount := scanner.Line()
 
-		var record dictionary.Entry
+		var record dict.Entry
 
 		// パース
 		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
@@ -126,7 +180,7 @@ func checkSorted(reader io.Reader) []error {
 			continue
 		}
 
-		if prevKey != "" && compareKeys(prevKey, record.Key, orderMap) > 0 {
+		if prevKey != "" && compare(prevKey, record.Key) > 0 {
 			errors = append(errors, fmt.Errorf("line %d: key %q is out of order after %q", lineCount, record.Key, prevKey))
 		}
 
@@ -142,68 +196,38 @@ func checkSorted(reader io.Reader) []error {
 
 // compareKeys returns -1 if prevKey < currentKey, 0 if equal, 1 if prevKey > currentKey according to kana order map
 func compareKeys(prevKey string, currentKey string, orderMap map[rune]int) int {
-	prevRunes := []rune(prevKey)
-	currentRunes := []rune(currentKey)
-
-	for i := 0; i < len(prevRunes) && i < len(currentRunes); i++ {
-		prevRune, prevOk := orderMap[prevRunes[i]]
-		currentRune, currentOk := orderMap[currentRunes[i]]
-
-		if !prevOk || !currentOk {
-			if prevRunes[i] == currentRunes[i] {
-				continue
-			}
-			if prevRunes[i] < currentRunes[i] {
-				return -1
-			}
-			return 1
-		}
-
-		if prevRune < currentRune {
-			return -1
-		}
-
-		if prevRune > currentRune {
-			return 1
-		}
-	}
-
-	if len(prevRunes) == len(currentRunes) {
-		return 0
-	}
-
-	if len(prevRunes) < len(currentRunes) {
-		return -1
-	}
-
-	return 1
+	return dict.CompareWithOrder(prevKey, currentKey, orderMap)
 }
 
 // sortData ソート済みデータ作成関数
-func sortData(reader io.Reader, orderMap map[rune]int) ([]dictionary.Entry, error) {
-	scanner := json.NewDecoder(reader)
+func sortData(reader io.Reader, orderMap map[rune]int) ([]dict.Entry, error) {
+	return sortDataBy(reader, func(a string, b string) int {
+		return compareKeys(a, b, orderMap)
+	})
+}
 
-	var records []dictionary.Entry
+// sortDataBy は compare の順序でソート済みデータを作成する
+func sortDataBy(reader io.Reader, compare func(a string, b string) int) ([]dict.Entry, error) {
+	entryReader := dict.NewReader(reader)
+	entryReader.SetMaxLineSize(maxLineSize)
+
+	var records []dict.Entry
 
 	// ファイルパース
-	for scanner.More() {
-		var record dictionary.Entry
+	for {
+		record, err := entryReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
 
-		if err := scanner.Decode(&record); err != nil {
-			return nil, fmt.Errorf("parse error")
+		if err != nil {
+			return nil, fmt.Errorf("parse error: %w", err)
 		}
 
 		records = append(records, record)
 	}
 
-	// 1行だけなら修正不要のため、離脱
-	if len(records) <= 1 {
-		return records, nil
-	}
-
-	sort.SliceStable(records, func(i, j int) bool {
-		return compareKeys(records[i].Key, records[j].Key, orderMap) < 0
-	})
+	dict.Sort(records, compare)
 
 	return records, nil
 }
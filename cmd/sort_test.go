@@ -73,3 +73,72 @@ func TestSortData(t *testing.T) {
 	}
 
 }
+
+func TestSortData_Numeric(t *testing.T) {
+	reader := strings.NewReader(strings.Join([]string{
+		`{"key":"あい","value":["愛"]}`,
+		`{"key":"#がつ","value":["#1月"]}`,
+		`{"key":"#かい","value":["#1回"]}`,
+	}, "\n"))
+
+	sorted, err := sortData(reader, dictionary.SortOrder())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedKeys := []string{"#かい", "#がつ", "あい"}
+
+	for i, e := range sorted {
+		if e.Key != expectedKeys[i] {
+			t.Fatalf("expected key %q at index %d, got %q", expectedKeys[i], i, e.Key)
+		}
+	}
+}
+
+func TestLookupDirectoryConfig(t *testing.T) {
+	configs := map[string]directoryConfig{
+		"jsonl":        {Collation: "gojuon"},
+		"jsonl/export": {Collation: "codepoint"},
+	}
+
+	if config, _ := lookupDirectoryConfig(configs, "jsonl/export/a.jsonl"); config.Collation != "codepoint" {
+		t.Fatalf("expected deepest config, got %q", config.Collation)
+	}
+
+	if config, _ := lookupDirectoryConfig(configs, "jsonl/2_char_jukugo/01-a.jsonl"); config.Collation != "gojuon" {
+		t.Fatalf("expected parent config, got %q", config.Collation)
+	}
+
+	if _, ok := lookupDirectoryConfig(configs, "jsonlx/a.jsonl"); ok {
+		t.Fatalf("expected no config for sibling directory")
+	}
+}
+
+func TestCheckSorted_LongLine(t *testing.T) {
+	reader := strings.NewReader(strings.Join([]string{
+		longEntryLine("い"),
+		`{"key":"あ","value":["a"]}`,
+	}, "\n"))
+
+	errs := checkSorted(reader)
+
+	if len(errs) != 1 || !strings.HasPrefix(errs[0].Error(), "line 2:") {
+		t.Fatalf("expected 1 error on line 2, got %v", errs)
+	}
+}
+
+func TestLookupDirectoryConfig_Overlay(t *testing.T) {
+	configs := map[string]directoryConfig{
+		"jsonl":               {Collation: "jis"},
+		"jsonl/2_char_jukugo": {CandidateLength: lengthRange{Min: 2, Max: 2}},
+	}
+
+	config, _ := lookupDirectoryConfig(configs, "jsonl/2_char_jukugo/01-a.jsonl")
+	if config.Collation != "jis" || config.CandidateLength.Max != 2 {
+		t.Fatalf("expected parent collation with child constraints, got %+v", config)
+	}
+
+	if err := (directoryConfig{CharClasses: []string{"emoji"}}).validate(); err == nil {
+		t.Fatalf("expected unknown char class error")
+	}
+}
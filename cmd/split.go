@@ -0,0 +1,457 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	dict "siguma0013/reskk-dictionary/pkg/dictionary"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// オプション
+var (
+	splitRoot     string
+	splitDir      string
+	splitEncoding string
+	isSplitPrune  bool
+	isSplitDryRun bool
+)
+
+var splitCmd = &cobra.Command{
+	Use:          "split <file>",
+	Short:        "マージ済みファイルや SKK-JISYO のエントリを jsonl 配下の辞書ファイルへ振り分けるコマンド",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// SKK-JISYO は送りありエントリを読み込まないため、それらのキーが入力に無いものとして削除されてしまう
+		if isSplitPrune && !strings.HasSuffix(args[0], ".jsonl") {
+			return fmt.Errorf("--prune cannot be used with an SKK-JISYO input (okuri-ari entries are not read)")
+		}
+
+		entries, okuriAri, err := loadUserDictionary(args[0], splitEncoding)
+		if err != nil {
+			return err
+		}
+
+		if okuriAri > 0 {
+			fmt.Fprintf(os.Stderr, "warning: %s: skipped %d okuri-ari entries (only okuri-nasi entries are split)\n", args[0], okuriAri)
+		}
+
+		configs, err := loadDirectoryConfig(directoryConfigPath)
+		if err != nil {
+			return err
+		}
+
+		layout, err := loadSplitLayout(splitRoot, configs)
+		if err != nil {
+			return err
+		}
+
+		skipped := layout.apply(entries, splitDir, isSplitPrune)
+
+		for _, message := range skipped {
+			fmt.Fprintf(os.Stderr, "skipped: %s\n", message)
+		}
+
+		changed := 0
+
+		for _, file := range layout.files {
+			added, updated, removed := file.changes()
+			if added+updated+removed == 0 {
+				continue
+			}
+
+			changed++
+			fmt.Printf("%s: +%d ~%d -%d\n", file.path, added, updated, removed)
+
+			if isSplitDryRun {
+				continue
+			}
+
+			if err := writeSplitFile(file.path, file.entries); err != nil {
+				return err
+			}
+		}
+
+		if changed == 0 {
+			fmt.Println("No changes")
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	splitCmd.Flags().StringVar(&splitRoot, "root", "jsonl", "root directory of the dictionary files")
+	splitCmd.Flags().StringVar(&splitDir, "dir", "jsonl/2_char_jukugo", "directory of the initial files (AllowInitials) that receive new keys")
+	splitCmd.Flags().StringVar(&splitEncoding, "encoding", "auto", "encoding of an SKK-JISYO input (auto, euc-jp, utf-8)")
+	splitCmd.Flags().BoolVar(&isSplitPrune, "prune", false, "remove keys and candidates that are missing from the input (jsonl input only)")
+	splitCmd.Flags().BoolVar(&isSplitDryRun, "dry-run", false, "print the changes without writing files")
+	rootCmd.AddCommand(splitCmd)
+}
+
+// splitFile は振り分け先の辞書ファイル1つ分の内容
+type splitFile struct {
+	path    string
+	compare func(a string, b string) int // 新しいキーを挿入する照合順序 (ディレクトリ設定)
+	before  []dict.Entry                 // 振り分け前の内容
+	entries []dict.Entry                 // 振り分け後の内容
+}
+
+// changes は振り分け前後で追加・変更・削除されたキーの数を返す
+// 候補の他に品詞・タグ・出典などのメタデータの変更も数える
+func (f *splitFile) changes() (added int, changed int, removed int) {
+	before := make(map[string]string)
+
+	for _, entry := range f.before {
+		before[entry.Key], _ = dict.Format(entry)
+	}
+
+	for _, entry := range f.entries {
+		line, _ := dict.Format(entry)
+
+		current, ok := before[entry.Key]
+		switch {
+		case !ok:
+			added++
+		case current != line:
+			changed++
+		}
+
+		delete(before, entry.Key)
+	}
+
+	return added, changed, len(before)
+}
+
+// find は key のエントリの位置を返す、無い時は -1
+func (f *splitFile) find(key string) int {
+	return slices.IndexFunc(f.entries, func(entry dict.Entry) bool {
+		return entry.Key == key
+	})
+}
+
+// has は key のエントリに value の候補がある時 true を返す
+func (f *splitFile) has(key string, value string) bool {
+	index := f.find(key)
+
+	return index >= 0 && slices.Contains(f.entries[index].Value, value)
+}
+
+// set は key の候補を values にする
+//   - 既存のエントリは source にメタデータ (品詞・タグ・出典) がある時だけ source のものにする
+//   - prune でなければ values に無い既存の候補を後ろに残す
+//   - 候補が無くなったエントリは削除する
+//   - 新しいエントリはファイルの照合順序で挿入する
+func (f *splitFile) set(source dict.Entry, values []string, prune bool) {
+	index := f.find(source.Key)
+
+	if index < 0 {
+		if len(values) == 0 {
+			return
+		}
+
+		entry := source
+		entry.Value = values
+		entry.Weight = splitWeight(values, source.Weight, nil)
+
+		f.entries = insertEntry(f.entries, entry, f.compare)
+
+		return
+	}
+
+	current := f.entries[index]
+
+	if !prune {
+		values = dict.Union(values, current.Value)
+	}
+
+	if len(values) == 0 {
+		f.entries = slices.Delete(f.entries, index, index+1)
+		return
+	}
+
+	current.Weight = splitWeight(values, source.Weight, current.Weight)
+	current.Value = values
+
+	// SKK-JISYO のようにメタデータを持たない入力で既存のメタデータを消さない
+	if source.Pos != "" {
+		current.Pos = source.Pos
+	}
+
+	if len(source.Tags) > 0 {
+		current.Tags = source.Tags
+	}
+
+	if !source.Provenance.Empty() {
+		current.Provenance = source.Provenance
+	}
+
+	f.entries[index] = current
+}
+
+// splitWeight は values の重みを source → current の優先順で集める
+func splitWeight(values []string, source map[string]int, current map[string]int) map[string]int {
+	var weight map[string]int
+
+	for _, value := range values {
+		w, ok := source[value]
+		if !ok {
+			w, ok = current[value]
+		}
+
+		if !ok {
+			continue
+		}
+
+		if weight == nil {
+			weight = make(map[string]int)
+		}
+
+		weight[value] = w
+	}
+
+	return weight
+}
+
+// insertEntry は entries の compare 順で最初に entry より大きいキーの前に entry を挿入する
+// 並び順が崩れているファイルでも既存の行は動かさない
+func insertEntry(entries []dict.Entry, entry dict.Entry, compare func(a string, b string) int) []dict.Entry {
+	index := slices.IndexFunc(entries, func(current dict.Entry) bool {
+		return compare(current.Key, entry.Key) > 0
+	})
+
+	if index < 0 {
+		return append(entries, entry)
+	}
+
+	return slices.Insert(entries, index, entry)
+}
+
+// splitLayout は振り分け先となる辞書ファイルの一覧
+type splitLayout struct {
+	configs   map[string]directoryConfig
+	files     []*splitFile
+	owners    map[string][]*splitFile    // キーを持つ編集可能なファイル (パス順)
+	generated map[string]map[string]bool // 生成ファイルが提供するキーごとの候補
+}
+
+// loadSplitLayout は root 配下の辞書ファイルを読み込む
+// 生成ファイル (生成ファイル一覧に記録されたもの) と削除リストは編集せず、生成ファイルの候補は振り分けから除く
+func loadSplitLayout(root string, configs map[string]directoryConfig) (*splitLayout, error) {
+	paths, err := resolveDictionaryFiles(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", root, err)
+	}
+
+	manifest, err := loadGeneratedManifest(generatedManifestPath)
+	if err != nil {
+		return nil, err
+	}
+
+	layout := &splitLayout{
+		configs:   configs,
+		owners:    make(map[string][]*splitFile),
+		generated: make(map[string]map[string]bool),
+	}
+
+	for _, path := range paths {
+		if dict.IsDenyFile(path) {
+			continue
+		}
+
+		entries, err := readEntries(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := manifest.Files[filepath.ToSlash(filepath.Clean(path))]; ok {
+			for _, entry := range entries {
+				if layout.generated[entry.Key] == nil {
+					layout.generated[entry.Key] = make(map[string]bool)
+				}
+
+				for _, value := range entry.Value {
+					layout.generated[entry.Key][value] = true
+				}
+			}
+
+			continue
+		}
+
+		compare, err := resolveCollation(configs, path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		file := &splitFile{path: path, compare: compare, before: entries, entries: slices.Clone(entries)}
+		layout.files = append(layout.files, file)
+
+		for _, entry := range entries {
+			if !slices.Contains(layout.owners[entry.Key], file) {
+				layout.owners[entry.Key] = append(layout.owners[entry.Key], file)
+			}
+		}
+	}
+
+	return layout, nil
+}
+
+// initialFile は dir 配下の key の読みの頭文字に対応するファイルを返す
+// 存在しないファイルは空のファイルとして追加する
+func (l *splitLayout) initialFile(dir string, key string) (*splitFile, error) {
+	name, ok := initialFileName(key)
+	if !ok {
+		return nil, fmt.Errorf("no file for the initial")
+	}
+
+	path := filepath.Join(dir, name)
+
+	for _, file := range l.files {
+		if filepath.Clean(file.path) == path {
+			return file, nil
+		}
+	}
+
+	compare, err := resolveCollation(l.configs, path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	file := &splitFile{path: path, compare: compare}
+	l.files = append(l.files, file)
+	slices.SortFunc(l.files, func(a *splitFile, b *splitFile) int {
+		return strings.Compare(a.path, b.path)
+	})
+
+	return file, nil
+}
+
+// initialFileName は key の読みの頭文字を許可するファイル名 (AllowInitials) を返す
+func initialFileName(key string) (string, bool) {
+	for _, initial := range key {
+		name, ok := initialGyo()[string(initial)]
+		if !ok {
+			return "", false
+		}
+
+		return name + ".jsonl", true
+	}
+
+	return "", false
+}
+
+// apply は entries を辞書ファイルへ振り分ける
+//   - 既存のキーはそのキーを持つファイル、既存の候補はその候補を持つファイルに残す
+//   - 新しいキーは dir 配下の頭文字のファイルに追加する
+//   - 生成ファイルが提供する候補は振り分けない
+//   - prune の時は entries に無いキーと候補を削除する
+//
+// 振り分けられなかったキーと候補は理由と共に返す
+func (l *splitLayout) apply(entries []dict.Entry, dir string, prune bool) []string {
+	var skipped []string
+
+	seen := make(map[string]bool)
+
+	for _, entry := range entries {
+		if entry.Key == "" {
+			continue
+		}
+
+		seen[entry.Key] = true
+
+		owners := l.owners[entry.Key]
+		assigned := make(map[*splitFile][]string)
+
+		// 複数のファイルにあるキーの出典はマージで全ファイル分がまとめられているため、各ファイルの出典を残す
+		if len(owners) > 1 {
+			entry.Provenance = dict.Provenance{}
+		}
+
+		for _, value := range entry.Value {
+			if l.generated[entry.Key][value] {
+				continue
+			}
+
+			index := slices.IndexFunc(owners, func(file *splitFile) bool {
+				return file.has(entry.Key, value)
+			})
+
+			if index >= 0 {
+				assigned[owners[index]] = append(assigned[owners[index]], value)
+				continue
+			}
+
+			var target *splitFile
+
+			if len(owners) > 0 {
+				target = owners[0]
+			} else {
+				file, err := l.initialFile(dir, entry.Key)
+				if err != nil {
+					skipped = append(skipped, fmt.Sprintf("%s /%s/: %v", entry.Key, value, err))
+					continue
+				}
+
+				target = file
+			}
+
+			config, _ := lookupDirectoryConfig(l.configs, target.path)
+			if errs := checkConstraints(dict.Entry{Key: entry.Key, Value: []string{value}}, config); len(errs) > 0 {
+				skipped = append(skipped, fmt.Sprintf("%s /%s/: %s: %v", entry.Key, value, target.path, errs[0]))
+				continue
+			}
+
+			assigned[target] = append(assigned[target], value)
+		}
+
+		for _, file := range l.files {
+			values, ok := assigned[file]
+			if ok || (prune && slices.Contains(owners, file)) {
+				file.set(entry, values, prune)
+			}
+		}
+	}
+
+	if !prune {
+		return skipped
+	}
+
+	for key, owners := range l.owners {
+		if seen[key] {
+			continue
+		}
+
+		for _, file := range owners {
+			file.set(dict.Entry{Key: key}, nil, true)
+		}
+	}
+
+	return skipped
+}
+
+// writeSplitFile は entries を正規フォーマットで path に書き出す
+func writeSplitFile(path string, entries []dict.Entry) error {
+	content, err := formatEntries(entries)
+	if err != nil {
+		return err
+	}
+
+	if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, content) {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return nil
+}
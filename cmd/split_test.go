@@ -0,0 +1,315 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	dict "siguma0013/reskk-dictionary/pkg/dictionary"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// setupSplitLayout は一時ディレクトリに jsonl 配下の辞書ファイルと生成ファイル一覧を作成する
+func setupSplitLayout(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	jukugo := filepath.Join(dir, "jsonl", "2_char_jukugo")
+
+	if err := os.MkdirAll(jukugo, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	writeTestFile(t, filepath.Join(jukugo, "01-a.jsonl"), strings.Join([]string{
+		`{"key": "あんごう", "value": ["暗号"]}`,
+		`{"key": "いちらん", "value": ["一覧"]}`,
+	}, "\n")+"\n")
+	writeTestFile(t, filepath.Join(dir, "jsonl", "number.jsonl"), `{"key": "いち", "value": ["一", "壱"]}`+"\n")
+	writeTestFile(t, filepath.Join(dir, "jsonl", "number_counter.jsonl"), `{"key": "いちかい", "value": ["一階"]}`+"\n")
+	writeTestFile(t, filepath.Join(dir, "jsonl", "number.deny.jsonl"), `{"key": "いち"}`+"\n")
+
+	generatedManifestPath = filepath.Join(dir, "generated.yml")
+	writeTestFile(t, generatedManifestPath, strings.Join([]string{
+		"files:",
+		"  " + filepath.ToSlash(filepath.Join(dir, "jsonl", "number_counter.jsonl")) + ":",
+		"    generator: counters",
+		"    hash: sha256:0",
+	}, "\n")+"\n")
+
+	return dir
+}
+
+func splitEntries(t *testing.T, layout *splitLayout, path string) []dict.Entry {
+	t.Helper()
+
+	for _, file := range layout.files {
+		if file.path == path {
+			return file.entries
+		}
+	}
+
+	t.Fatalf("%s is not in the layout", path)
+
+	return nil
+}
+
+func TestSplitApply(t *testing.T) {
+	defer func(manifest string) { generatedManifestPath = manifest }(generatedManifestPath)
+
+	dir := setupSplitLayout(t)
+	jukugo := filepath.Join(dir, "jsonl", "2_char_jukugo")
+
+	configs := map[string]directoryConfig{
+		jukugo: {CandidateLength: lengthRange{Min: 2, Max: 2}},
+	}
+
+	layout, err := loadSplitLayout(filepath.Join(dir, "jsonl"), configs)
+	if err != nil {
+		t.Fatalf("loadSplitLayout: %v", err)
+	}
+
+	skipped := layout.apply([]dict.Entry{
+		{Key: "あいさつ", Value: []string{"挨拶"}},
+		{Key: "いち", Value: []string{"壹", "一"}},
+		{Key: "いちかい", Value: []string{"一階"}},
+		{Key: "まくら", Value: []string{"枕木"}},
+		{Key: "かたかな", Value: []string{"片仮名"}},
+		{Key: "ヴぁ", Value: []string{"字"}},
+	}, jukugo, false)
+
+	if len(skipped) != 2 || !strings.HasPrefix(skipped[0], "かたかな") || !strings.HasPrefix(skipped[1], "ヴぁ") {
+		t.Errorf("unexpected skipped: %v", skipped)
+	}
+
+	// 新しいキーは頭文字のファイルのソート位置に入る
+	gotA := splitEntries(t, layout, filepath.Join(jukugo, "01-a.jsonl"))
+	if keys := entryKeys(gotA); !slices.Equal(keys, []string{"あいさつ", "あんごう", "いちらん"}) {
+		t.Errorf("01-a.jsonl keys = %v", keys)
+	}
+
+	// 既存のキーは元のファイルに残り、入力に無い候補も残る
+	gotNumber := splitEntries(t, layout, filepath.Join(dir, "jsonl", "number.jsonl"))
+	if len(gotNumber) != 1 || !slices.Equal(gotNumber[0].Value, []string{"壹", "一", "壱"}) {
+		t.Errorf("number.jsonl = %v", gotNumber)
+	}
+
+	// 存在しない頭文字のファイルは新しく作る
+	gotMa := splitEntries(t, layout, filepath.Join(jukugo, "07-ma.jsonl"))
+	if keys := entryKeys(gotMa); !slices.Equal(keys, []string{"まくら"}) {
+		t.Errorf("07-ma.jsonl keys = %v", keys)
+	}
+
+	// 生成ファイルと削除リストは振り分け先にならない
+	for _, file := range layout.files {
+		if strings.HasSuffix(file.path, "number_counter.jsonl") || strings.HasSuffix(file.path, ".deny.jsonl") {
+			t.Errorf("%s should not be in the layout", file.path)
+		}
+	}
+}
+
+func TestSplitApply_Prune(t *testing.T) {
+	defer func(manifest string) { generatedManifestPath = manifest }(generatedManifestPath)
+
+	dir := setupSplitLayout(t)
+	jukugo := filepath.Join(dir, "jsonl", "2_char_jukugo")
+
+	layout, err := loadSplitLayout(filepath.Join(dir, "jsonl"), nil)
+	if err != nil {
+		t.Fatalf("loadSplitLayout: %v", err)
+	}
+
+	layout.apply([]dict.Entry{
+		{Key: "いち", Value: []string{"一"}},
+		{Key: "いちらん", Value: []string{"一覧"}},
+	}, jukugo, true)
+
+	gotA := splitEntries(t, layout, filepath.Join(jukugo, "01-a.jsonl"))
+	if keys := entryKeys(gotA); !slices.Equal(keys, []string{"いちらん"}) {
+		t.Errorf("01-a.jsonl keys = %v", keys)
+	}
+
+	gotNumber := splitEntries(t, layout, filepath.Join(dir, "jsonl", "number.jsonl"))
+	if len(gotNumber) != 1 || !slices.Equal(gotNumber[0].Value, []string{"一"}) {
+		t.Errorf("number.jsonl = %v", gotNumber)
+	}
+}
+
+func TestSplitApply_Metadata(t *testing.T) {
+	defer func(manifest string) { generatedManifestPath = manifest }(generatedManifestPath)
+
+	dir := setupSplitLayout(t)
+	jukugo := filepath.Join(dir, "jsonl", "2_char_jukugo")
+	path := filepath.Join(jukugo, "01-a.jsonl")
+
+	writeTestFile(t, path, strings.Join([]string{
+		`{"key": "あんごう", "value": ["暗号"], "pos": "noun", "tags": ["tech"]}`,
+		`{"key": "いちらん", "value": ["一覧"], "pos": "noun"}`,
+	}, "\n")+"\n")
+
+	layout, err := loadSplitLayout(filepath.Join(dir, "jsonl"), nil)
+	if err != nil {
+		t.Fatalf("loadSplitLayout: %v", err)
+	}
+
+	layout.apply([]dict.Entry{
+		{Key: "あんごう", Value: []string{"暗号"}, Pos: "noun", Tags: []string{"tech", "archaic"}, Provenance: dict.Provenance{Contributor: "alice"}},
+		{Key: "いちらん", Value: []string{"一覧"}},
+	}, jukugo, false)
+
+	// 外部で編集したメタデータは反映し、メタデータの無い入力では既存のものを残す
+	got := splitEntries(t, layout, path)
+	if !slices.Equal(got[0].Tags, []string{"tech", "archaic"}) || got[0].Contributor != "alice" || got[1].Pos != "noun" {
+		t.Errorf("01-a.jsonl = %+v", got)
+	}
+
+	for _, file := range layout.files {
+		if file.path != path {
+			continue
+		}
+
+		if added, changed, removed := file.changes(); added != 0 || changed != 1 || removed != 0 {
+			t.Errorf("changes = +%d ~%d -%d", added, changed, removed)
+		}
+	}
+}
+
+func TestSplitApply_SpreadProvenance(t *testing.T) {
+	defer func(manifest string) { generatedManifestPath = manifest }(generatedManifestPath)
+
+	dir := setupSplitLayout(t)
+	jukugo := filepath.Join(dir, "jsonl", "2_char_jukugo")
+	local := filepath.Join(jukugo, "01-a.jsonl")
+	imported := filepath.Join(dir, "jsonl", "imported.jsonl")
+
+	writeTestFile(t, local, `{"key": "あんごう", "value": ["暗号"], "license": "CC0-1.0"}`+"\n")
+	writeTestFile(t, imported, `{"key": "あんごう", "value": ["暗合"], "source": "SKK-JISYO.L", "license": "GPL-2.0-or-later"}`+"\n")
+
+	layout, err := loadSplitLayout(filepath.Join(dir, "jsonl"), nil)
+	if err != nil {
+		t.Fatalf("loadSplitLayout: %v", err)
+	}
+
+	// マージ済みファイルの出典は2つのファイルの出典をまとめたもの
+	layout.apply([]dict.Entry{
+		{Key: "あんごう", Value: []string{"暗号", "暗合"}, Provenance: dict.Provenance{Source: "SKK-JISYO.L", License: "CC0-1.0 AND GPL-2.0-or-later"}},
+	}, jukugo, false)
+
+	if got := splitEntries(t, layout, local); got[0].Provenance != (dict.Provenance{License: "CC0-1.0"}) {
+		t.Errorf("01-a.jsonl = %+v", got)
+	}
+
+	if got := splitEntries(t, layout, imported); got[0].License != "GPL-2.0-or-later" {
+		t.Errorf("imported.jsonl = %+v", got)
+	}
+}
+
+func TestSplitApply_Collation(t *testing.T) {
+	defer func(manifest string) { generatedManifestPath = manifest }(generatedManifestPath)
+
+	dir := setupSplitLayout(t)
+	jukugo := filepath.Join(dir, "jsonl", "2_char_jukugo")
+	path := filepath.Join(jukugo, "04-ta.jsonl")
+
+	writeTestFile(t, path, `{"key": "つつみ", "value": ["包み"]}`+"\n")
+
+	// codepoint では っ (U+3063) が つ (U+3064) より前に並ぶ
+	configs := map[string]directoryConfig{
+		jukugo: {Collation: "codepoint"},
+	}
+
+	layout, err := loadSplitLayout(filepath.Join(dir, "jsonl"), configs)
+	if err != nil {
+		t.Fatalf("loadSplitLayout: %v", err)
+	}
+
+	layout.apply([]dict.Entry{
+		{Key: "つっこみ", Value: []string{"突込"}},
+	}, jukugo, false)
+
+	if keys := entryKeys(splitEntries(t, layout, path)); !slices.Equal(keys, []string{"つっこみ", "つつみ"}) {
+		t.Errorf("04-ta.jsonl keys = %v", keys)
+	}
+}
+
+func TestSplitCommand_WritesOnlyChanges(t *testing.T) {
+	defer func(manifest string) { generatedManifestPath = manifest }(generatedManifestPath)
+	defer func(root string, dir string) { splitRoot, splitDir = root, dir }(splitRoot, splitDir)
+
+	dir := setupSplitLayout(t)
+	splitRoot = filepath.Join(dir, "jsonl")
+	splitDir = filepath.Join(dir, "jsonl", "2_char_jukugo")
+
+	// 正規フォーマットでない行はエントリが同じなら書き換えない
+	number := filepath.Join(dir, "jsonl", "number.jsonl")
+	writeTestFile(t, number, `{"key":"いち","value":["一","壱"]}`+"\n")
+
+	input := filepath.Join(dir, "input.jsonl")
+	writeTestFile(t, input, strings.Join([]string{
+		`{"key": "あいさつ", "value": ["挨拶"]}`,
+		`{"key": "いち", "value": ["一", "壱"]}`,
+	}, "\n")+"\n")
+
+	if err := splitCmd.RunE(splitCmd, []string{input}); err != nil {
+		t.Fatalf("split command failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(splitDir, "01-a.jsonl"))
+	if err != nil {
+		t.Fatalf("read 01-a.jsonl: %v", err)
+	}
+
+	if !strings.HasPrefix(string(content), `{"key": "あいさつ", "value": ["挨拶"]}`+"\n") {
+		t.Errorf("01-a.jsonl = %q", content)
+	}
+
+	content, err = os.ReadFile(number)
+	if err != nil {
+		t.Fatalf("read number.jsonl: %v", err)
+	}
+
+	if string(content) != `{"key":"いち","value":["一","壱"]}`+"\n" {
+		t.Errorf("number.jsonl should not be rewritten: %q", content)
+	}
+}
+
+func TestSplitCommand_PruneSkkJisyo(t *testing.T) {
+	defer func(manifest string) { generatedManifestPath = manifest }(generatedManifestPath)
+	defer func(root string, dir string, prune bool) {
+		splitRoot, splitDir, isSplitPrune = root, dir, prune
+	}(splitRoot, splitDir, isSplitPrune)
+
+	dir := setupSplitLayout(t)
+	splitRoot = filepath.Join(dir, "jsonl")
+	splitDir = filepath.Join(dir, "jsonl", "2_char_jukugo")
+	isSplitPrune = true
+
+	// 送りありのキーは SKK-JISYO から読み込まれないため、--prune で削除されてはいけない
+	okuri := filepath.Join(dir, "jsonl", "okuri.jsonl")
+	writeTestFile(t, okuri, `{"key": "かえr", "value": ["帰"]}`+"\n")
+
+	input := filepath.Join(dir, ".skk-jisyo")
+	writeTestFile(t, input, strings.Join([]string{
+		";; okuri-ari entries.",
+		"かえr /帰/",
+		";; okuri-nasi entries.",
+		"いち /一/壱/",
+	}, "\n")+"\n")
+
+	if err := splitCmd.RunE(splitCmd, []string{input}); err == nil {
+		t.Fatalf("expected --prune to be refused for an SKK-JISYO input")
+	}
+
+	if entries, err := readEntries(okuri); err != nil || len(entries) != 1 {
+		t.Errorf("okuri.jsonl = %v, %v", entries, err)
+	}
+}
+
+func entryKeys(entries []dict.Entry) []string {
+	var keys []string
+
+	for _, entry := range entries {
+		keys = append(keys, entry.Key)
+	}
+
+	return keys
+}
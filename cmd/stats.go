@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"siguma0013/reskk-dictionary/internal/dictionary"
+	dict "siguma0013/reskk-dictionary/pkg/dictionary"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+)
+
+// otherGyo は AllowInitials のどの行にも属さない読みの集計名
+const otherGyo = "other"
+
+// オプション
+var (
+	statsFormat string
+)
+
+var statsCmd = &cobra.Command{
+	Use:          "stats [path]",
+	Short:        "辞書の件数や分布などの統計情報を表示するコマンド",
+	Long:         "path (既定は merge_order.yml、ディレクトリや jsonl ファイルも可) の統計情報を表示する",
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if statsFormat != "text" && statsFormat != "json" {
+			return fmt.Errorf("unknown format %q (available: text, json)", statsFormat)
+		}
+
+		path := "merge_order.yml"
+		if len(args) == 1 {
+			path = args[0]
+		}
+
+		files, err := resolveDictionaryFiles(path)
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", path, err)
+		}
+
+		stats, err := makeStats(files)
+		if err != nil {
+			return err
+		}
+
+		if statsFormat == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetEscapeHTML(false)
+			encoder.SetIndent("", "  ")
+
+			return encoder.Encode(stats)
+		}
+
+		printStats(os.Stdout, stats)
+
+		return nil
+	},
+}
+
+func init() {
+	statsCmd.Flags().StringVar(&statsFormat, "format", "text", "output format (text, json)")
+	rootCmd.AddCommand(statsCmd)
+}
+
+// fileStats はファイル単位の件数
+type fileStats struct {
+	Path       string `json:"path"`
+	Entries    int    `json:"entries"`
+	Candidates int    `json:"candidates"`
+}
+
+// dictionaryStats は辞書全体の統計情報
+//   - Files, Entries, Candidates はファイルの行単位で数える
+//   - それ以外はマージ後のキー単位で数える
+type dictionaryStats struct {
+	Files            []fileStats    `json:"files"`
+	Entries          int            `json:"entries"`
+	Candidates       int            `json:"candidates"`
+	UniqueKeys       int            `json:"unique_keys"`
+	SharedKeys       int            `json:"shared_keys"` // 複数のファイルで定義されているキーの数
+	KeyLengths       map[int]int    `json:"key_lengths"`
+	CandidatesPerKey map[int]int    `json:"candidates_per_key"`
+	Gyo              map[string]int `json:"gyo"`
+}
+
+// makeStats は paths の辞書ファイルから統計情報を作成する
+func makeStats(paths []string) (dictionaryStats, error) {
+	stats := dictionaryStats{
+		Files:            []fileStats{},
+		KeyLengths:       make(map[int]int),
+		CandidatesPerKey: make(map[int]int),
+		Gyo:              make(map[string]int),
+	}
+
+	// キーごとの定義ファイル数
+	keyFiles := make(map[string]int)
+
+	for _, path := range paths {
+		// 削除リストはマージで適用するだけで、エントリとしては数えない
+		if dict.IsDenyFile(path) {
+			continue
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return stats, err
+		}
+
+		reader := dict.NewReader(file)
+		reader.SetMaxLineSize(maxLineSize)
+
+		current := fileStats{Path: path}
+		seen := make(map[string]bool)
+
+		for {
+			entry, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			if err != nil {
+				file.Close()
+				return stats, fmt.Errorf("%s: %w", path, err)
+			}
+
+			current.Entries++
+			current.Candidates += len(entry.Value)
+
+			if !seen[entry.Key] {
+				seen[entry.Key] = true
+				keyFiles[entry.Key]++
+			}
+		}
+
+		file.Close()
+
+		stats.Files = append(stats.Files, current)
+		stats.Entries += current.Entries
+		stats.Candidates += current.Candidates
+	}
+
+	for _, count := range keyFiles {
+		if count > 1 {
+			stats.SharedKeys++
+		}
+	}
+
+	entries, err := makeMergeData(paths)
+	if err != nil {
+		return stats, err
+	}
+
+	gyo := initialGyo()
+
+	for _, entry := range entries {
+		stats.UniqueKeys++
+		stats.KeyLengths[utf8.RuneCountInString(entry.Key)]++
+		stats.CandidatesPerKey[len(entry.Value)]++
+
+		initial, _ := utf8.DecodeRuneInString(entry.Key)
+
+		name, ok := gyo[string(initial)]
+		if !ok {
+			name = otherGyo
+		}
+
+		stats.Gyo[name]++
+	}
+
+	return stats, nil
+}
+
+// initialGyo は AllowInitials から頭文字 → 行 (ファイル名から拡張子を除いたもの) の対応を作成する
+func initialGyo() map[string]string {
+	gyo := make(map[string]string)
+
+	for file, initials := range dictionary.AllowInitials {
+		for _, initial := range initials {
+			gyo[initial] = strings.TrimSuffix(file, ".jsonl")
+		}
+	}
+
+	return gyo
+}
+
+// printStats は統計情報をテキストで出力する
+func printStats(writer io.Writer, stats dictionaryStats) {
+	fmt.Fprintln(writer, "Files:")
+
+	for _, file := range stats.Files {
+		note := ""
+		if file.Entries == 0 {
+			note = " (empty)"
+		}
+
+		fmt.Fprintf(writer, "  %s: %d entries, %d candidates%s\n", file.Path, file.Entries, file.Candidates, note)
+	}
+
+	fmt.Fprintf(writer, "Total: %d entries, %d candidates\n", stats.Entries, stats.Candidates)
+	fmt.Fprintf(writer, "Unique keys: %d\n", stats.UniqueKeys)
+	fmt.Fprintf(writer, "Shared keys: %d\n", stats.SharedKeys)
+
+	printDistribution(writer, "Key length", stats.KeyLengths)
+	printDistribution(writer, "Candidates per key", stats.CandidatesPerKey)
+
+	fmt.Fprintln(writer, "Gyo:")
+
+	for _, name := range slices.Sorted(maps.Keys(stats.Gyo)) {
+		fmt.Fprintf(writer, "  %s: %d\n", name, stats.Gyo[name])
+	}
+}
+
+// printDistribution は分布を値の小さい順に出力する
+func printDistribution(writer io.Writer, title string, distribution map[int]int) {
+	fmt.Fprintf(writer, "%s:\n", title)
+
+	for _, value := range slices.Sorted(maps.Keys(distribution)) {
+		fmt.Fprintf(writer, "  %d: %d\n", value, distribution[value])
+	}
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMakeStats(t *testing.T) {
+	d := t.TempDir()
+
+	files := map[string]string{
+		"a.jsonl": `{"key": "あい", "value": ["愛", "藍"]}` + "\n" + `{"key": "きのう", "value": ["機能"]}` + "\n",
+		"b.jsonl": `{"key": "きのう", "value": ["昨日"]}` + "\n" + `{"key": "ゃ", "value": ["ャ"]}` + "\n",
+		"c.jsonl": "",
+	}
+
+	var paths []string
+
+	for _, name := range []string{"a.jsonl", "b.jsonl", "c.jsonl"} {
+		path := filepath.Join(d, name)
+		if err := os.WriteFile(path, []byte(files[name]), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+
+		paths = append(paths, path)
+	}
+
+	stats, err := makeStats(paths)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stats.Entries != 4 || stats.Candidates != 5 || stats.UniqueKeys != 3 || stats.SharedKeys != 1 {
+		t.Fatalf("unexpected totals: %+v", stats)
+	}
+
+	if stats.Files[2].Entries != 0 {
+		t.Fatalf("expected empty file to be counted, got %+v", stats.Files[2])
+	}
+
+	if stats.KeyLengths[1] != 1 || stats.KeyLengths[2] != 1 || stats.KeyLengths[3] != 1 {
+		t.Fatalf("unexpected key lengths: %v", stats.KeyLengths)
+	}
+
+	if stats.CandidatesPerKey[1] != 1 || stats.CandidatesPerKey[2] != 2 {
+		t.Fatalf("unexpected candidates per key: %v", stats.CandidatesPerKey)
+	}
+
+	if stats.Gyo["01-a"] != 1 || stats.Gyo["02-ka"] != 1 || stats.Gyo[otherGyo] != 1 {
+		t.Fatalf("unexpected gyo: %v", stats.Gyo)
+	}
+}
+
+func TestMakeStats_DenyFile(t *testing.T) {
+	d := t.TempDir()
+
+	paths := []string{filepath.Join(d, "a.jsonl"), filepath.Join(d, "a.deny.jsonl")}
+
+	writeTestFile(t, paths[0], `{"key": "あい", "value": ["愛", "藍"]}`+"\n"+`{"key": "きのう", "value": ["機能"]}`+"\n")
+	writeTestFile(t, paths[1], `{"key": "あい", "value": ["藍"]}`+"\n")
+
+	stats, err := makeStats(paths)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 削除リストはファイルにもエントリにも共有キーにも数えず、マージ結果には適用する
+	if len(stats.Files) != 1 || stats.Entries != 2 || stats.SharedKeys != 0 {
+		t.Fatalf("unexpected totals: %+v", stats)
+	}
+
+	if stats.CandidatesPerKey[1] != 2 {
+		t.Fatalf("unexpected candidates per key: %v", stats.CandidatesPerKey)
+	}
+}
@@ -0,0 +1,25 @@
+package dictionary
+
+import "unicode"
+
+// CharClasses は directory_config.yml の char_classes で指定できる文字種を定義する
+var CharClasses = map[string]func(r rune) bool{
+	"kanji": func(r rune) bool {
+		return unicode.Is(unicode.Han, r) || r == '々'
+	},
+	"hiragana": func(r rune) bool {
+		return unicode.Is(unicode.Hiragana, r) || r == 'ー'
+	},
+	"katakana": func(r rune) bool {
+		return unicode.Is(unicode.Katakana, r) || r == 'ー'
+	},
+	"digit": func(r rune) bool {
+		return r >= '0' && r <= '9'
+	},
+	"fullwidth_digit": func(r rune) bool {
+		return r >= '０' && r <= '９'
+	},
+	"latin": func(r rune) bool {
+		return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+	},
+}
@@ -0,0 +1,86 @@
+package dictionary
+
+import "unicode/utf8"
+
+// Collations は sort で選択可能な照合順序の名前を定義する
+//   - gojuon: 辞書ファイル既定の五十音順 (sortOrder)
+//   - codepoint: Unicode コードポイント順 (SKK-JISYO の送りなしエントリ向け)
+//   - reverse: コードポイントの逆順 (SKK-JISYO の送りありエントリ向け)
+//   - jis: JIS X 4061 風の照合順
+var Collations = []string{"gojuon", "codepoint", "reverse", "jis"}
+
+// DefaultCollation は照合順序が指定されていない時に利用する照合順序
+const DefaultCollation = "gojuon"
+
+// voicedKana は濁音とその清音の対応を定義する
+var voicedKana = [][2]string{
+	{"がぎぐげご", "かきくけこ"},
+	{"ざじずぜぞ", "さしすせそ"},
+	{"だぢづでど", "たちつてと"},
+	{"ばびぶべぼ", "はひふへほ"},
+	{"ゔ", "う"},
+}
+
+// semiVoicedKana は半濁音とその清音の対応を定義する
+var semiVoicedKana = [][2]string{
+	{"ぱぴぷぺぽ", "はひふへほ"},
+}
+
+// smallKana は小書き仮名とその直音の対応を定義する
+var smallKana = [][2]string{
+	{"ぁぃぅぇぉっゃゅょゎ", "あいうえおつやゆよわ"},
+}
+
+// vowelRows は長音記号を母音に置き換えるための段を定義する
+// 各行の先頭文字がその段の母音となる
+var vowelRows = []string{
+	"あかさたなはまやらわ",
+	"いきしちにひみり",
+	"うくすつぬふむゆる",
+	"えけせてねへめれ",
+	"おこそとのほもよろを",
+}
+
+// KanaFold は JIS X 4061 風照合で利用する仮名の分解結果
+type KanaFold struct {
+	Base    rune // 清音・直音に正規化した文字
+	Voicing int  // 0: 清音, 1: 濁音, 2: 半濁音
+	Small   bool // 小書き仮名の時 true
+}
+
+// JisFold は仮名の分解結果をソートアルゴリズムで利用しやすいmapで提供する
+// カタカナは呼び出し側でひらがなに寄せてから参照すること
+func JisFold() map[rune]KanaFold {
+	foldMap := make(map[rune]KanaFold)
+
+	add := func(pairs [][2]string, voicing int, small bool) {
+		for _, pair := range pairs {
+			bases := []rune(pair[1])
+
+			for index, r := range []rune(pair[0]) {
+				foldMap[r] = KanaFold{Base: bases[index], Voicing: voicing, Small: small}
+			}
+		}
+	}
+
+	add(voicedKana, 1, false)
+	add(semiVoicedKana, 2, false)
+	add(smallKana, 0, true)
+
+	return foldMap
+}
+
+// Vowels は仮名からその段の母音を引くmapを提供する
+func Vowels() map[rune]rune {
+	vowelMap := make(map[rune]rune)
+
+	for _, row := range vowelRows {
+		vowel, _ := utf8.DecodeRuneInString(row)
+
+		for _, r := range row {
+			vowelMap[r] = vowel
+		}
+	}
+
+	return vowelMap
+}
@@ -0,0 +1,63 @@
+package dictionary
+
+// DefaultCounterMax は助数詞と組み合わせる数の既定の最大値
+const DefaultCounterMax = 10
+
+// CounterRule は助数詞ごとの読みの規則
+type CounterRule struct {
+	// Max は組み合わせる数の最大値、0 の時は DefaultCounterMax
+	Max int
+	// Rendaku は さん の後で連濁する時 true (さんがい、さんぼん)
+	Rendaku bool
+	// Native は促音化しない和語の助数詞の時 true (いちくみ)
+	Native bool
+	// Readings は規則に従わない数の読み、指定した数は規則から読みを作らない
+	Readings map[int][]string
+}
+
+// CounterRules は助数詞 (number_word.jsonl の候補) ごとの例外を定義する
+// 表に無い助数詞は CounterRule の既定値で読む
+var CounterRules = map[string]CounterRule{
+	"月": {Max: 12, Readings: map[int][]string{4: {"しがつ"}, 7: {"しちがつ"}, 9: {"くがつ"}}},
+	"日": {Max: 31, Readings: map[int][]string{
+		1: {"ついたち", "いちにち"}, 2: {"ふつか"}, 3: {"みっか"}, 4: {"よっか"}, 5: {"いつか"},
+		6: {"むいか"}, 7: {"なのか", "なぬか"}, 8: {"ようか"}, 9: {"ここのか"}, 10: {"とおか"},
+		14: {"じゅうよっか"}, 20: {"はつか"}, 24: {"にじゅうよっか"},
+	}},
+	"年": {Readings: map[int][]string{4: {"よねん"}}},
+	"本": {Rendaku: true},
+	"階": {Rendaku: true},
+	"組": {Native: true},
+}
+
+// counterSokuon は助数詞の頭の仮名ごとに、その前で促音化する数の読みの語尾を定義する
+var counterSokuon = []struct {
+	heads   string
+	endings []string
+}{
+	{"かきくけこ", []string{"いち", "ろく", "はち", "じゅう", "ひゃく"}},
+	{"さしすせそたちつてと", []string{"いち", "はち", "じゅう"}},
+	{"はひふへほぱぴぷぺぽ", []string{"いち", "ろく", "はち", "じゅう", "ひゃく"}},
+}
+
+// SokuonEndings は数の読みの語尾と促音化した形の対応を定義する
+var SokuonEndings = map[string][]string{
+	"いち":  {"いっ"},
+	"ろく":  {"ろっ"},
+	"はち":  {"はっ"},
+	"じゅう": {"じゅっ", "じっ"},
+	"ひゃく": {"ひゃっ"},
+}
+
+// CounterSokuon は助数詞の頭の仮名と促音化する数の読みの語尾の対応をmapで提供する
+func CounterSokuon() map[rune][]string {
+	sokuonMap := make(map[rune][]string)
+
+	for _, row := range counterSokuon {
+		for _, head := range row.heads {
+			sokuonMap[head] = row.endings
+		}
+	}
+
+	return sokuonMap
+}
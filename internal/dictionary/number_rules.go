@@ -0,0 +1,22 @@
+package dictionary
+
+// Numerals は number.jsonl の先頭の候補 (漢数字) が表す数を定義する
+var Numerals = map[string]int{
+	"一": 1, "二": 2, "三": 3, "四": 4, "五": 5, "六": 6, "七": 7, "八": 8, "九": 9,
+	"十": 10, "百": 100, "千": 1000, "万": 10000,
+}
+
+// NumberSoundChanges は数字の読みに桁の読みを続けた時の音便を定義する
+// 表に無い組み合わせはそのまま繋げて読む
+var NumberSoundChanges = map[string]string{
+	"さんひゃく": "さんびゃく",
+	"ろくひゃく": "ろっぴゃく",
+	"はちひゃく": "はっぴゃく",
+	"さんせん":  "さんぜん",
+	"はちせん":  "はっせん",
+}
+
+// NumericConversionTypes は SKK で定義されている数値変換タイプ (候補の #n の n)
+//   - 0: 無変換 (1024)、1: 全角 (１０２４)、2: 漢数字 位取りなし (一〇二四)、3: 漢数字 位取りあり (千二十四)
+//   - 4: 数値再変換、5: 大字 (壱阡弐拾四)、8: 桁区切り (1,024)、9: 将棋 (３四)
+var NumericConversionTypes = []int{0, 1, 2, 3, 4, 5, 8, 9}
@@ -0,0 +1,33 @@
+package dictionary
+
+// sokuonKana は促音化 (せつ → せっ) する語末の仮名を定義する
+var sokuonKana = []string{"つ", "ち", "く", "き"}
+
+// Rendaku は連濁 (語頭の清音 → 濁音・半濁音) の対応をmapで提供する
+// 半濁音は は行 の後に促音・撥音が続く時 (いっぱい、さんぽ) のために含める
+func Rendaku() map[rune][]rune {
+	rendakuMap := make(map[rune][]rune)
+
+	for _, pairs := range [][][2]string{voicedKana, semiVoicedKana} {
+		for _, pair := range pairs {
+			bases := []rune(pair[1])
+
+			for index, r := range []rune(pair[0]) {
+				rendakuMap[bases[index]] = append(rendakuMap[bases[index]], r)
+			}
+		}
+	}
+
+	return rendakuMap
+}
+
+// Sokuon は促音化する語末の仮名をmapで提供する
+func Sokuon() map[rune]bool {
+	sokuonMap := make(map[rune]bool)
+
+	for _, s := range sokuonKana {
+		sokuonMap[[]rune(s)[0]] = true
+	}
+
+	return sokuonMap
+}
@@ -1,6 +1,7 @@
 package dictionary
 
 // sortOrder は辞書のソート順を定義する
+// 数値変換エントリ (#かい など) の # は定義外の文字としてコードポイント順で仮名より前に並ぶ
 var sortOrder = []string{
 	"あ", "ぁ", "い", "ぃ", "う", "ぅ", "え", "ぇ", "お", "ぉ",
 	"か", "が", "き", "ぎ", "く", "ぐ", "け", "げ", "こ", "ご",
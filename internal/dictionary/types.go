@@ -1,7 +0,0 @@
-package dictionary
-
-// Entry は辞書ファイルの構造定義
-type Entry struct {
-	Key   string   `json:"key"`
-	Value []string `json:"value"`
-}
@@ -0,0 +1,23 @@
+package dictionary
+
+// PartsOfSpeech はエントリの pos に指定できる品詞を定義する
+//   - noun: 名詞
+//   - suru-verb: サ変名詞 (する を付けて動詞になる)
+//   - adjectival-noun: 形容動詞 (な を付けて修飾する)
+//   - adverb: 副詞
+//   - counter: 助数詞
+//   - numeral: 数詞
+//   - prefix: 接頭辞
+//   - suffix: 接尾辞
+var PartsOfSpeech = []string{
+	"noun", "suru-verb", "adjectival-noun", "adverb", "counter", "numeral", "prefix", "suffix",
+}
+
+// Tags はエントリの tags に指定できる分類を定義する
+//   - tech: 技術用語
+//   - place: 地名
+//   - name: 人名
+//   - archaic: 古語・旧字など現代では使われにくい語
+var Tags = []string{
+	"tech", "place", "name", "archaic",
+}
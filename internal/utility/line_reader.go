@@ -0,0 +1,109 @@
+package utility
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrLineTooLong は MaxSize を超える行を読み込んだ時のエラー
+var ErrLineTooLong = errors.New("line too long")
+
+// LineError は読み込みに失敗した行番号を保持するエラー
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// LineReader は bufio.Scanner と同じ使い方ができる行単位の読み込み
+//   - 既定では行の長さに上限を設けない
+//   - MaxSize に正の値を設定すると、それを超える行で ErrLineTooLong を返す
+//   - 行末の \n と \r\n は取り除く
+type LineReader struct {
+	MaxSize int
+
+	reader *bufio.Reader
+	buffer []byte
+	line   int
+	err    error
+}
+
+// NewLineReader は reader を行単位で読み込む LineReader を作成する
+func NewLineReader(reader io.Reader) *LineReader {
+	return &LineReader{reader: bufio.NewReader(reader)}
+}
+
+// Scan は次の行を読み込む、読み込める行が無い時やエラー時は false を返す
+func (r *LineReader) Scan() bool {
+	if r.err != nil {
+		return false
+	}
+
+	r.buffer = r.buffer[:0]
+
+	for {
+		chunk, err := r.reader.ReadSlice('\n')
+		r.buffer = append(r.buffer, chunk...)
+
+		if r.MaxSize > 0 && len(bytes.TrimRight(r.buffer, "\r\n")) > r.MaxSize {
+			r.err = &LineError{Line: r.line + 1, Err: fmt.Errorf("%w (limit %d bytes)", ErrLineTooLong, r.MaxSize)}
+			return false
+		}
+
+		// バッファに収まらない長い行は続きを読み込む
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+
+		if errors.Is(err, io.EOF) {
+			// 改行で終わるファイルの末尾
+			if len(r.buffer) == 0 {
+				return false
+			}
+			break
+		}
+
+		if err != nil {
+			r.err = &LineError{Line: r.line + 1, Err: err}
+			return false
+		}
+
+		break
+	}
+
+	r.line++
+	r.buffer = bytes.TrimSuffix(r.buffer, []byte("\n"))
+	r.buffer = bytes.TrimSuffix(r.buffer, []byte("\r"))
+
+	return true
+}
+
+// Bytes は直前に読み込んだ行を返す、次の Scan で上書きされる
+func (r *LineReader) Bytes() []byte {
+	return r.buffer
+}
+
+// Text は直前に読み込んだ行を文字列で返す
+func (r *LineReader) Text() string {
+	return string(r.buffer)
+}
+
+// Line は直前に読み込んだ行の行番号 (1始まり) を返す
+func (r *LineReader) Line() int {
+	return r.line
+}
+
+// Err は読み込み中に発生したエラーを返す、EOF はエラーとしない
+func (r *LineReader) Err() error {
+	return r.err
+}
@@ -0,0 +1,63 @@
+package utility
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLineReader_Lines(t *testing.T) {
+	reader := NewLineReader(strings.NewReader("a\r\n\nb\nc"))
+
+	var lines []string
+	var numbers []int
+
+	for reader.Scan() {
+		lines = append(lines, reader.Text())
+		numbers = append(numbers, reader.Line())
+	}
+
+	if reader.Err() != nil {
+		t.Fatalf("unexpected error: %v", reader.Err())
+	}
+
+	expected := []string{"a", "", "b", "c"}
+	if strings.Join(lines, "|") != strings.Join(expected, "|") {
+		t.Fatalf("expected %q, got %q", expected, lines)
+	}
+
+	if numbers[3] != 4 {
+		t.Fatalf("expected last line number 4, got %d", numbers[3])
+	}
+}
+
+func TestLineReader_LongLine(t *testing.T) {
+	long := strings.Repeat("あ", 100*1024)
+	reader := NewLineReader(strings.NewReader("short\n" + long + "\nend\n"))
+
+	count := 0
+	for reader.Scan() {
+		count++
+
+		if count == 2 && reader.Text() != long {
+			t.Fatalf("expected long line to be read intact, got %d bytes", len(reader.Bytes()))
+		}
+	}
+
+	if reader.Err() != nil || count != 3 {
+		t.Fatalf("expected 3 lines without error, got %d: %v", count, reader.Err())
+	}
+}
+
+func TestLineReader_MaxSize(t *testing.T) {
+	reader := NewLineReader(strings.NewReader("short\n" + strings.Repeat("a", 100) + "\n"))
+	reader.MaxSize = 10
+
+	for reader.Scan() {
+	}
+
+	var lineError *LineError
+	if !errors.As(reader.Err(), &lineError) || lineError.Line != 2 || !errors.Is(reader.Err(), ErrLineTooLong) {
+		t.Fatalf("expected line too long error on line 2, got %v", reader.Err())
+	}
+}
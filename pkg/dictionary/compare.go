@@ -0,0 +1,159 @@
+package dictionary
+
+import (
+	"cmp"
+	"fmt"
+	"siguma0013/reskk-dictionary/internal/dictionary"
+	"slices"
+	"strings"
+)
+
+var (
+	gojuonOrder = dictionary.SortOrder()
+	jisFold     = dictionary.JisFold()
+	jisVowels   = dictionary.Vowels()
+)
+
+// collations は照合順序名と比較関数の対応
+var collations = map[string]func(a string, b string) int{
+	"gojuon":    Compare,
+	"codepoint": strings.Compare,
+	"reverse":   compareReverse,
+	"jis":       compareJis,
+}
+
+// Collations は選択可能な照合順序の名前を返す
+func Collations() []string {
+	return slices.Clone(dictionary.Collations)
+}
+
+// Collation は照合順序名から比較関数を取得する
+// 空文字の時は既定の照合順序 (五十音順) を返す
+func Collation(name string) (func(a string, b string) int, error) {
+	if name == "" {
+		name = dictionary.DefaultCollation
+	}
+
+	compare, ok := collations[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown collation %q (available: %s)", name, strings.Join(dictionary.Collations, ", "))
+	}
+
+	return compare, nil
+}
+
+// Compare は辞書ファイル既定の五十音順でキーを比較する
+// a < b の時 -1、等しい時 0、a > b の時 1 を返す
+func Compare(a string, b string) int {
+	return CompareWithOrder(a, b, gojuonOrder)
+}
+
+// CompareWithOrder は orderMap の文字順でキーを比較する
+// orderMap に無い文字同士はコードポイント順で比較する
+func CompareWithOrder(a string, b string, orderMap map[rune]int) int {
+	aRunes := []rune(a)
+	bRunes := []rune(b)
+
+	for i := 0; i < len(aRunes) && i < len(bRunes); i++ {
+		aOrder, aOk := orderMap[aRunes[i]]
+		bOrder, bOk := orderMap[bRunes[i]]
+
+		if !aOk || !bOk {
+			if aRunes[i] == bRunes[i] {
+				continue
+			}
+			if aRunes[i] < bRunes[i] {
+				return -1
+			}
+			return 1
+		}
+
+		if aOrder < bOrder {
+			return -1
+		}
+
+		if aOrder > bOrder {
+			return 1
+		}
+	}
+
+	return cmp.Compare(len(aRunes), len(bRunes))
+}
+
+// compareReverse はコードポイントの逆順で比較する
+func compareReverse(a string, b string) int {
+	return strings.Compare(b, a)
+}
+
+// jisWeight は JIS X 4061 風照合における1文字分の重み
+type jisWeight struct {
+	primary  int // 清音・直音・ひらがなに正規化した文字
+	voicing  int // 0: 清音, 1: 濁音, 2: 半濁音
+	size     int // 0: 小書き, 1: 直音
+	katakana int // 0: ひらがな, 1: カタカナ
+}
+
+// jisWeights は読みを照合用の重みの列に分解する
+func jisWeights(key string) []jisWeight {
+	weights := make([]jisWeight, 0, len(key))
+
+	var prev rune
+
+	for _, r := range key {
+		weight := jisWeight{size: 1}
+
+		// カタカナはひらがなに寄せる
+		if r >= 'ァ' && r <= 'ヶ' {
+			r -= 'ァ' - 'ぁ'
+			weight.katakana = 1
+		}
+
+		// 長音記号は直前の文字の母音として扱う
+		if r == 'ー' {
+			if vowel, ok := jisVowels[prev]; ok {
+				r = vowel
+			}
+		}
+
+		if fold, ok := jisFold[r]; ok {
+			r = fold.Base
+			weight.voicing = fold.Voicing
+
+			if fold.Small {
+				weight.size = 0
+			}
+		}
+
+		weight.primary = int(r)
+		weights = append(weights, weight)
+		prev = r
+	}
+
+	return weights
+}
+
+// compareJis は JIS X 4061 風の照合順で比較する
+// 清音化した文字列 → 清濁 → 直音/小書き → ひらがな/カタカナ の順に比較し、最後はコードポイント順とする
+func compareJis(a string, b string) int {
+	aWeights := jisWeights(a)
+	bWeights := jisWeights(b)
+
+	levels := []func(w jisWeight) int{
+		func(w jisWeight) int { return w.primary },
+		func(w jisWeight) int { return w.voicing },
+		func(w jisWeight) int { return w.size },
+		func(w jisWeight) int { return w.katakana },
+	}
+
+	for _, level := range levels {
+		result := slices.CompareFunc(aWeights, bWeights, func(x jisWeight, y jisWeight) int {
+			return cmp.Compare(level(x), level(y))
+		})
+
+		if result != 0 {
+			return result
+		}
+	}
+
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,46 @@
+package dictionary
+
+import "testing"
+
+func TestCollations(t *testing.T) {
+	tests := []struct {
+		collation string
+		less      string
+		greater   string
+	}{
+		{"gojuon", "か", "が"},
+		{"gojuon", "つ", "っ"},
+		{"codepoint", "っ", "つ"},
+		{"codepoint", "か", "き"},
+		{"reverse", "き", "か"},
+		{"jis", "か", "が"},
+		{"jis", "かつお", "がっこう"},
+		{"jis", "しょう", "しよう"},
+		{"jis", "かあ", "カー"},
+		{"gojuon", "#かい", "あ"},
+		{"jis", "#かい", "あ"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.collation+"/"+test.less+"<"+test.greater, func(t *testing.T) {
+			compare, err := Collation(test.collation)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if compare(test.less, test.greater) >= 0 {
+				t.Fatalf("expected %q < %q", test.less, test.greater)
+			}
+
+			if compare(test.greater, test.less) <= 0 {
+				t.Fatalf("expected %q > %q", test.greater, test.less)
+			}
+		})
+	}
+}
+
+func TestCollation_Unknown(t *testing.T) {
+	if _, err := Collation("unknown"); err == nil {
+		t.Fatalf("expected error for unknown collation")
+	}
+}
@@ -0,0 +1,146 @@
+package dictionary
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DenySuffix は削除リストの辞書ファイルの拡張子
+const DenySuffix = ".deny.jsonl"
+
+// IsDenyFile は path が削除リスト (.deny.jsonl) か判定する
+func IsDenyFile(path string) bool {
+	return strings.HasSuffix(path, DenySuffix)
+}
+
+// DenyList はマージ結果から取り除く候補の一覧
+//   - value を持つエントリ: そのキーの指定した候補だけを取り除く
+//   - value の無いエントリ: そのキーを丸ごと取り除く
+type DenyList map[string]map[string]bool
+
+// ReadDenyList は削除リストを読み込む
+func ReadDenyList(reader io.Reader, maxLineSize int) (DenyList, error) {
+	deny := make(DenyList)
+
+	if err := deny.read(reader, maxLineSize); err != nil {
+		return nil, err
+	}
+
+	return deny, nil
+}
+
+// LoadDenyList は sources のうち削除リストの入力を全て読み込み1つにまとめる
+func LoadDenyList(sources []Source, maxLineSize int) (DenyList, error) {
+	deny := make(DenyList)
+
+	for _, source := range sources {
+		if !source.Deny {
+			continue
+		}
+
+		file, err := source.Open()
+		if err != nil {
+			return nil, err
+		}
+
+		err = deny.read(file, maxLineSize)
+		file.Close()
+
+		if err != nil {
+			return nil, fmt.Errorf("parse error: %s: %w", source.Name, err)
+		}
+	}
+
+	return deny, nil
+}
+
+// read は reader の削除リストを追加する
+func (d DenyList) read(reader io.Reader, maxLineSize int) error {
+	entryReader := NewReader(reader)
+	entryReader.SetMaxLineSize(maxLineSize)
+
+	for {
+		entry, err := entryReader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		d.add(entry)
+	}
+}
+
+// add はエントリを削除リストに追加する
+func (d DenyList) add(entry Entry) {
+	candidates, ok := d[entry.Key]
+
+	// キーごと削除済みであればそのまま
+	if ok && candidates == nil {
+		return
+	}
+
+	if len(entry.Value) == 0 {
+		d[entry.Key] = nil
+		return
+	}
+
+	if candidates == nil {
+		candidates = make(map[string]bool)
+		d[entry.Key] = candidates
+	}
+
+	for _, value := range entry.Value {
+		candidates[value] = true
+	}
+}
+
+// Denies は key の候補 value が削除対象か判定する
+func (d DenyList) Denies(key string, value string) bool {
+	candidates, ok := d[key]
+
+	return ok && (candidates == nil || candidates[value])
+}
+
+// Apply はエントリから削除対象の候補を取り除く
+// 候補が残らない時は false を返す
+func (d DenyList) Apply(entry Entry) (Entry, bool) {
+	if _, ok := d[entry.Key]; !ok {
+		return entry, true
+	}
+
+	var values []string
+
+	for _, value := range entry.Value {
+		if d.Denies(entry.Key, value) {
+			delete(entry.Weight, value)
+			continue
+		}
+
+		values = append(values, value)
+	}
+
+	entry.Value = values
+
+	return entry, len(values) > 0
+}
+
+// ValidateDeny は削除リストのエントリの内容を検証する
+// 削除リストでは value を省略できる
+func ValidateDeny(entry Entry) error {
+	if entry.Key == "" {
+		return ErrEmptyKey
+	}
+
+	for _, value := range entry.Value {
+		if value == "" {
+			return ErrEmptyCandidate
+		}
+	}
+
+	return nil
+}
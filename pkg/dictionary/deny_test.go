@@ -0,0 +1,58 @@
+package dictionary
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMerge_Deny(t *testing.T) {
+	deny := stringSource("upstream.deny.jsonl", `{"key": "きのう", "value": ["気能"]}`+"\n"+`{"key": "あい"}`)
+	deny.Deny = true
+
+	sources := []Source{
+		deny,
+		stringSource("a", `{"key": "あい", "value": ["愛"]}`+"\n"+`{"key": "きのう", "value": ["機能", "気能"], "weight": {"気能": 1}}`),
+		stringSource("b", `{"key": "かい", "value": ["回"]}`),
+	}
+
+	var entries []Entry
+
+	for entry, err := range Merge(sources, MergePolicy{}) {
+		if err != nil {
+			t.Fatalf("merge failed: %v", err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if len(entries) != 2 || entries[0].Key != "かい" || entries[1].Key != "きのう" {
+		t.Fatalf("expected denied key to be dropped, got %v", entries)
+	}
+
+	if !slices.Equal(entries[1].Value, []string{"機能"}) || len(entries[1].Weight) != 0 {
+		t.Fatalf("expected denied candidate to be removed, got %v", entries[1])
+	}
+}
+
+func TestDenyList(t *testing.T) {
+	deny := make(DenyList)
+	deny.add(Entry{Key: "あい", Value: []string{"哀"}})
+	deny.add(Entry{Key: "あい"})
+	deny.add(Entry{Key: "あい", Value: []string{"愛"}})
+
+	if !deny.Denies("あい", "藍") {
+		t.Fatalf("expected whole key to stay denied")
+	}
+
+	if _, ok := deny.Apply(Entry{Key: "あい", Value: []string{"藍"}}); ok {
+		t.Fatalf("expected entry to be dropped")
+	}
+
+	if err := ValidateDeny(Entry{Key: "あい"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !IsDenyFile("jsonl/upstream.deny.jsonl") || IsDenyFile("jsonl/number.jsonl") {
+		t.Fatalf("unexpected deny file detection")
+	}
+}
@@ -0,0 +1,123 @@
+package dictionary
+
+import "slices"
+
+// DiffResult はエントリ単位の差分
+type DiffResult struct {
+	Added   []Entry     `json:"added"`   // 新しい辞書にだけあるキー
+	Removed []Entry     `json:"removed"` // 古い辞書にだけあるキー
+	Changed []EntryDiff `json:"changed"` // 両方にあり候補が異なるキー
+}
+
+// EntryDiff は同じキーの候補の差分
+type EntryDiff struct {
+	Key       string   `json:"key"`
+	Added     []string `json:"added,omitempty"`
+	Removed   []string `json:"removed,omitempty"`
+	Reordered bool     `json:"reordered,omitempty"` // 共通の候補の並び順が変わった時 true
+	Before    []string `json:"before"`
+	After     []string `json:"after"`
+}
+
+// Empty は差分が無い時 true を返す
+func (d DiffResult) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Diff は2つの辞書をエントリ単位で比較する
+//   - 行の順序や書式は比較せず、同じキーが複数ある時は Union でまとめてから比較する
+//   - 同じキーが複数あるキーの候補の並び順はエントリの並びで変わるため、並び順の変化として扱わない
+//   - 結果はキーの五十音順に並べる
+func Diff(before []Entry, after []Entry) DiffResult {
+	beforeMap, beforeSpread := entryMap(before)
+	afterMap, afterSpread := entryMap(after)
+
+	result := DiffResult{Added: []Entry{}, Removed: []Entry{}, Changed: []EntryDiff{}}
+
+	for key, values := range afterMap {
+		if _, ok := beforeMap[key]; !ok {
+			result.Added = append(result.Added, Entry{Key: key, Value: values})
+		}
+	}
+
+	for key, beforeValues := range beforeMap {
+		afterValues, ok := afterMap[key]
+		if !ok {
+			result.Removed = append(result.Removed, Entry{Key: key, Value: beforeValues})
+			continue
+		}
+
+		if slices.Equal(beforeValues, afterValues) {
+			continue
+		}
+
+		diff := EntryDiff{
+			Key:     key,
+			Added:   subtract(afterValues, beforeValues),
+			Removed: subtract(beforeValues, afterValues),
+			Before:  beforeValues,
+			After:   afterValues,
+		}
+
+		if !beforeSpread[key] && !afterSpread[key] {
+			diff.Reordered = !slices.Equal(intersect(beforeValues, afterValues), intersect(afterValues, beforeValues))
+		}
+
+		if len(diff.Added) == 0 && len(diff.Removed) == 0 && !diff.Reordered {
+			continue
+		}
+
+		result.Changed = append(result.Changed, diff)
+	}
+
+	compareEntry := func(a Entry, b Entry) int { return Compare(a.Key, b.Key) }
+
+	slices.SortFunc(result.Added, compareEntry)
+	slices.SortFunc(result.Removed, compareEntry)
+	slices.SortFunc(result.Changed, func(a EntryDiff, b EntryDiff) int { return Compare(a.Key, b.Key) })
+
+	return result
+}
+
+// entryMap はキーごとに候補をまとめる
+// spread は複数のエントリに分かれているキー
+func entryMap(entries []Entry) (values map[string][]string, spread map[string]bool) {
+	values = make(map[string][]string, len(entries))
+	spread = make(map[string]bool)
+
+	for _, entry := range entries {
+		if _, ok := values[entry.Key]; ok {
+			spread[entry.Key] = true
+		}
+
+		values[entry.Key] = Union(values[entry.Key], entry.Value)
+	}
+
+	return values, spread
+}
+
+// subtract は a のうち b に含まれない値を a の順に返す
+func subtract(a []string, b []string) []string {
+	var result []string
+
+	for _, value := range a {
+		if !slices.Contains(b, value) {
+			result = append(result, value)
+		}
+	}
+
+	return result
+}
+
+// intersect は a のうち b にも含まれる値を a の順に返す
+func intersect(a []string, b []string) []string {
+	var result []string
+
+	for _, value := range a {
+		if slices.Contains(b, value) {
+			result = append(result, value)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,80 @@
+package dictionary
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	before := []Entry{
+		{Key: "あい", Value: []string{"愛"}},
+		{Key: "きのう", Value: []string{"機能", "昨日"}},
+		{Key: "かい", Value: []string{"回", "会"}},
+		{Key: "こう", Value: []string{"高", "校"}},
+		{Key: "さくじょ", Value: []string{"削除"}},
+	}
+
+	after := []Entry{
+		{Key: "さくじょ", Value: []string{"削除"}},
+		{Key: "かい", Value: []string{"会"}},
+		{Key: "かい", Value: []string{"回", "貝"}},
+		{Key: "きのう", Value: []string{"機能"}},
+		{Key: "こう", Value: []string{"校", "高"}},
+		{Key: "えき", Value: []string{"駅"}},
+	}
+
+	result := Diff(before, after)
+
+	if len(result.Added) != 1 || result.Added[0].Key != "えき" {
+		t.Fatalf("unexpected added: %v", result.Added)
+	}
+
+	if len(result.Removed) != 1 || result.Removed[0].Key != "あい" {
+		t.Fatalf("unexpected removed: %v", result.Removed)
+	}
+
+	if len(result.Changed) != 3 {
+		t.Fatalf("expected 3 changed keys, got %v", result.Changed)
+	}
+
+	// 複数のエントリに分かれたキーは並び順の変化として扱わない
+	kai := result.Changed[0]
+	if kai.Key != "かい" || kai.Reordered || !slices.Equal(kai.Added, []string{"貝"}) || len(kai.Removed) != 0 {
+		t.Fatalf("unexpected diff for かい: %+v", kai)
+	}
+
+	kinou := result.Changed[1]
+	if kinou.Key != "きのう" || kinou.Reordered || !slices.Equal(kinou.Removed, []string{"昨日"}) {
+		t.Fatalf("unexpected diff for きのう: %+v", kinou)
+	}
+
+	kou := result.Changed[2]
+	if kou.Key != "こう" || !kou.Reordered || len(kou.Added) != 0 || len(kou.Removed) != 0 {
+		t.Fatalf("unexpected diff for こう: %+v", kou)
+	}
+
+	if !Diff(before, before).Empty() {
+		t.Fatalf("expected no diff for identical dictionaries")
+	}
+}
+
+func TestDiff_SpreadKey(t *testing.T) {
+	// 同じ内容でファイルの分け方だけが異なる辞書
+	before := []Entry{
+		{Key: "きのう", Value: []string{"機能"}},
+		{Key: "きのう", Value: []string{"昨日"}},
+	}
+
+	after := []Entry{
+		{Key: "きのう", Value: []string{"昨日"}},
+		{Key: "きのう", Value: []string{"機能"}},
+	}
+
+	if result := Diff(before, after); !result.Empty() {
+		t.Fatalf("expected no diff, got %+v", result)
+	}
+
+	if result := Diff(before, []Entry{{Key: "きのう", Value: []string{"昨日", "機能"}}}); !result.Empty() {
+		t.Fatalf("expected no diff against a merged entry, got %+v", result)
+	}
+}
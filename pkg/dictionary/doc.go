@@ -0,0 +1,14 @@
+// Package dictionary は Re:SKK 用辞書の JSONL 形式を扱う公開 API を提供する
+//
+// コマンド (cmd) が行っているパース、整形、ソート、マージはこのパッケージの薄いラッパーであり、
+// IME プラグインや Web ツールからも同じ規則で辞書を読み書きできる
+//
+//	reader := dictionary.NewReader(file)
+//	for {
+//		entry, err := reader.Read()
+//		if err == io.EOF {
+//			break
+//		}
+//		...
+//	}
+package dictionary
@@ -0,0 +1,181 @@
+package dictionary
+
+import (
+	"errors"
+	"fmt"
+	"siguma0013/reskk-dictionary/internal/dictionary"
+	"slices"
+	"strings"
+)
+
+// Entry は辞書ファイル1行分のエントリ
+type Entry struct {
+	Key    string         `json:"key"`
+	Value  []string       `json:"value"`
+	Weight map[string]int `json:"weight,omitempty"` // 値ごとの重み、大きいほど優先される
+	Pos    string         `json:"pos,omitempty"`    // 品詞 (PartsOfSpeech)
+	Tags   []string       `json:"tags,omitempty"`   // 分類タグ (Tags)
+	Provenance
+}
+
+// Provenance はエントリの出典 (出典の辞書、追加した人、ライセンス)
+// JSONL ではエントリのフィールドとして、merge_order.yml ではファイル単位の既定値として記述する
+type Provenance struct {
+	Source      string `json:"source,omitempty" yaml:"source"`           // 出典の辞書・コーパス (SKK-JISYO.L など)
+	Contributor string `json:"contributor,omitempty" yaml:"contributor"` // 追加した人
+	License     string `json:"license,omitempty" yaml:"license"`         // ライセンス (SPDX 識別子)
+}
+
+// Empty は出典が指定されていない時 true を返す
+func (p Provenance) Empty() bool {
+	return p == Provenance{}
+}
+
+// Overlay は既定の出典 p にエントリの出典 entry を重ねる
+// entry で指定された項目のみ上書きする
+func (p Provenance) Overlay(entry Provenance) Provenance {
+	if entry.Source != "" {
+		p.Source = entry.Source
+	}
+
+	if entry.Contributor != "" {
+		p.Contributor = entry.Contributor
+	}
+
+	if entry.License != "" {
+		p.License = entry.License
+	}
+
+	return p
+}
+
+var (
+	// ErrEmptyKey は key が空のエントリのエラー
+	ErrEmptyKey = errors.New("empty key")
+	// ErrEmptyValue は value が空のエントリのエラー
+	ErrEmptyValue = errors.New("empty value")
+	// ErrEmptyCandidate は value に空文字が含まれるエントリのエラー
+	ErrEmptyCandidate = errors.New("empty candidate")
+	// ErrInvalidNumeric は候補の # の後に変換タイプ (数字) が無いエントリのエラー
+	ErrInvalidNumeric = errors.New("invalid numeric conversion type")
+	// ErrUnknownNumeric は候補の #n の変換タイプが SKK で定義されていない (#6 など) エントリのエラー
+	ErrUnknownNumeric = errors.New("unknown numeric conversion type")
+	// ErrNumericMismatch は key の # と候補の #n の数が一致しないエントリのエラー
+	ErrNumericMismatch = errors.New("numeric placeholder count mismatch")
+	// ErrUnknownPos は pos が定義されていない品詞のエントリのエラー
+	ErrUnknownPos = errors.New("unknown part of speech")
+	// ErrUnknownTag は tags に定義されていないタグを含むエントリのエラー
+	ErrUnknownTag = errors.New("unknown tag")
+)
+
+// NumericPlaceholder は数値変換エントリで数字に置き換わる文字
+// key の # 1つに対して、候補には SKK で定義された変換タイプ付きの #n が1つ対応する
+const NumericPlaceholder = '#'
+
+// Validate はエントリの内容を検証する
+// 行の書式 (スペースの数など) は対象とせず、パース後の値だけを確認する
+func Validate(entry Entry) error {
+	if entry.Key == "" {
+		return ErrEmptyKey
+	}
+
+	if len(entry.Value) == 0 {
+		return ErrEmptyValue
+	}
+
+	for _, value := range entry.Value {
+		if value == "" {
+			return ErrEmptyCandidate
+		}
+
+		if err := validateNumeric(entry.Key, value); err != nil {
+			return err
+		}
+	}
+
+	if entry.Pos != "" && !slices.Contains(dictionary.PartsOfSpeech, entry.Pos) {
+		return fmt.Errorf("%w %q", ErrUnknownPos, entry.Pos)
+	}
+
+	for _, tag := range entry.Tags {
+		if !slices.Contains(dictionary.Tags, tag) {
+			return fmt.Errorf("%w %q", ErrUnknownTag, tag)
+		}
+	}
+
+	return nil
+}
+
+// PartsOfSpeech は pos に指定できる品詞を返す
+func PartsOfSpeech() []string {
+	return slices.Clone(dictionary.PartsOfSpeech)
+}
+
+// Tags は tags に指定できる分類を返す
+func Tags() []string {
+	return slices.Clone(dictionary.Tags)
+}
+
+// HasAnyTag はエントリが tags のいずれかを持つか判定する
+func HasAnyTag(entry Entry, tags []string) bool {
+	return slices.ContainsFunc(entry.Tags, func(tag string) bool {
+		return slices.Contains(tags, tag)
+	})
+}
+
+// IsNumericKey は key が数値変換エントリ (#かい など) か判定する
+func IsNumericKey(key string) bool {
+	return strings.ContainsRune(key, NumericPlaceholder)
+}
+
+// NumericTypes は候補に含まれる数値変換タイプ (#1回 の 1 など) を出現順に返す
+// # の後に数字が続かない箇所は -1 とする
+func NumericTypes(candidate string) []int {
+	var types []int
+
+	runes := []rune(candidate)
+
+	for i, r := range runes {
+		if r != NumericPlaceholder {
+			continue
+		}
+
+		if i+1 < len(runes) && runes[i+1] >= '0' && runes[i+1] <= '9' {
+			types = append(types, int(runes[i+1]-'0'))
+		} else {
+			types = append(types, -1)
+		}
+	}
+
+	return types
+}
+
+// validateNumeric は key と候補の数値変換タイプの整合性を検証する
+// 数値変換エントリでない時、候補の # は通常の文字 (C# など) として扱う
+func validateNumeric(key string, value string) error {
+	types := NumericTypes(value)
+
+	if !IsNumericKey(key) {
+		if slices.ContainsFunc(types, func(t int) bool { return t >= 0 }) {
+			return ErrNumericMismatch
+		}
+
+		return nil
+	}
+
+	if slices.Contains(types, -1) {
+		return ErrInvalidNumeric
+	}
+
+	for _, t := range types {
+		if !slices.Contains(dictionary.NumericConversionTypes, t) {
+			return fmt.Errorf("%w #%d", ErrUnknownNumeric, t)
+		}
+	}
+
+	if len(types) != strings.Count(key, string(NumericPlaceholder)) {
+		return ErrNumericMismatch
+	}
+
+	return nil
+}
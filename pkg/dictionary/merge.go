@@ -0,0 +1,341 @@
+package dictionary
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+)
+
+// Source はマージの入力、ソート済みか確認するため Open は複数回呼び出される
+type Source struct {
+	Name string
+	Open func() (io.ReadCloser, error)
+	// Deny が true の入力は削除リスト (DenyList) として扱い、候補を追加しない
+	Deny bool
+}
+
+// FileSource はファイルを入力とする Source を作成する
+// 拡張子が .deny.jsonl のファイルは削除リストとして扱う
+func FileSource(path string) Source {
+	return Source{
+		Name: path,
+		Open: func() (io.ReadCloser, error) {
+			return os.Open(path)
+		},
+		Deny: IsDenyFile(path),
+	}
+}
+
+// FileSources は複数のファイルを入力とする Source を作成する
+func FileSources(paths []string) []Source {
+	sources := make([]Source, 0, len(paths))
+
+	for _, path := range paths {
+		sources = append(sources, FileSource(path))
+	}
+
+	return sources
+}
+
+// MergePolicy は Merge の動作を指定する
+type MergePolicy struct {
+	// Compare は出力するキーの順序、nil の時は Compare (五十音順)
+	Compare func(a string, b string) int
+	// MaxLineSize は入力1行の最大バイト数、0 は無制限
+	MaxLineSize int
+	// IncludeTags が空でない時、いずれかのタグを持つエントリだけをマージする
+	IncludeTags []string
+	// ExcludeTags のいずれかのタグを持つエントリはマージしない
+	ExcludeTags []string
+}
+
+// Accepts はエントリがタグの条件を満たすか判定する
+func (p MergePolicy) Accepts(entry Entry) bool {
+	if len(p.IncludeTags) > 0 && !HasAnyTag(entry, p.IncludeTags) {
+		return false
+	}
+
+	return !HasAnyTag(entry, p.ExcludeTags)
+}
+
+// Merge は sources を k-way マージし、キーごとにまとめたエントリを policy.Compare の順に返す
+//   - 入力は policy.Compare の順にソート済みであることを前提に逐次読み込む
+//   - ソートされていない入力だけはメモリ上でソートしてから扱う
+//   - 同じキーの値は sources の順、入力内の行順に Union で結合する
+//   - キーの無いレコードは末尾にまとめる
+//   - policy のタグの条件を満たさないエントリは入力ごとに読み飛ばす
+//   - 出典の異なるエントリをまとめた時は項目ごとに全ての出典を残す (ライセンスは SPDX の AND 式)
+//   - 削除リストの入力は sources 上の位置に関わらず、全ての入力をまとめた結果に適用する
+func Merge(sources []Source, policy MergePolicy) iter.Seq2[Entry, error] {
+	compare := policy.Compare
+	if compare == nil {
+		compare = Compare
+	}
+
+	compareKey := func(a string, b string) int {
+		switch {
+		case a == "" && b == "":
+			return 0
+		case a == "":
+			return 1
+		case b == "":
+			return -1
+		}
+
+		return compare(a, b)
+	}
+
+	return func(yield func(Entry, error) bool) {
+		deny, err := LoadDenyList(sources, policy.MaxLineSize)
+		if err != nil {
+			yield(Entry{}, err)
+			return
+		}
+
+		queue := &mergeQueue{compare: compareKey}
+
+		// 終了時に全入力のクローズを強制
+		defer func() {
+			for _, cursor := range queue.cursors {
+				cursor.close()
+			}
+		}()
+
+		for index, source := range sources {
+			if source.Deny {
+				continue
+			}
+
+			cursor, err := openCursor(source, index, compareKey, policy.MaxLineSize)
+			if err != nil {
+				yield(Entry{}, err)
+				return
+			}
+
+			cursor.accept = policy.Accepts
+
+			ok, err := cursor.advance()
+			if err != nil || !ok {
+				cursor.close()
+
+				if err != nil {
+					yield(Entry{}, err)
+					return
+				}
+
+				continue
+			}
+
+			heap.Push(queue, cursor)
+		}
+
+		for queue.Len() > 0 {
+			// 先頭のキーと同じキーを持つエントリを全て取り出す
+			key := queue.cursors[0].current.Key
+			candidates := newCandidateSet(nil)
+
+			var weight map[string]int
+			var pos string
+			var tags []string
+
+			provenance := queue.cursors[0].current.Provenance
+
+			for queue.Len() > 0 && queue.cursors[0].current.Key == key {
+				cursor := queue.cursors[0]
+
+				candidates.add(cursor.current.Value)
+				weight = unionWeight(weight, cursor.current.Weight)
+				pos = unionPos(pos, cursor.current.Pos)
+				tags = unionTags(tags, cursor.current.Tags)
+				provenance = unionProvenance(provenance, cursor.current.Provenance)
+
+				ok, err := cursor.advance()
+				if err != nil {
+					yield(Entry{}, err)
+					return
+				}
+
+				if ok {
+					heap.Fix(queue, 0)
+					continue
+				}
+
+				cursor.close()
+				heap.Pop(queue)
+			}
+
+			entry, ok := deny.Apply(Entry{Key: key, Value: candidates.values, Weight: weight, Pos: pos, Tags: tags, Provenance: provenance})
+			if !ok {
+				continue
+			}
+
+			if !yield(entry, nil) {
+				return
+			}
+		}
+	}
+}
+
+// mergeCursor は k-way マージの入力1つ分のカーソル
+type mergeCursor struct {
+	order   int // sources 上の順序
+	current Entry
+	next    func() (Entry, error)
+	close   func() error
+	accept  func(entry Entry) bool // nil の時は全てのエントリを扱う
+}
+
+// openCursor はマージ入力を開く
+// ソート済みであれば逐次読み込み、そうでなければ全件をメモリ上でソートする
+func openCursor(source Source, order int, compare func(a string, b string) int, maxLineSize int) (*mergeCursor, error) {
+	sorted, err := isSorted(source, compare, maxLineSize)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := source.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	reader := NewReader(file)
+	reader.SetMaxLineSize(maxLineSize)
+
+	if sorted {
+		cursor := &mergeCursor{order: order, close: file.Close}
+
+		cursor.next = func() (Entry, error) {
+			entry, err := reader.Read()
+			if err != nil && !errors.Is(err, io.EOF) {
+				return entry, fmt.Errorf("parse error: %s: %w", source.Name, err)
+			}
+
+			return entry, err
+		}
+
+		return cursor, nil
+	}
+
+	// ここから先はソートされていない入力
+	defer file.Close()
+
+	var entries []Entry
+
+	for {
+		entry, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("parse error: %s: %w", source.Name, err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	Sort(entries, compare)
+
+	cursor := &mergeCursor{order: order, close: func() error { return nil }}
+
+	cursor.next = func() (Entry, error) {
+		if len(entries) == 0 {
+			return Entry{}, io.EOF
+		}
+
+		entry := entries[0]
+		entries = entries[1:]
+
+		return entry, nil
+	}
+
+	return cursor, nil
+}
+
+// advance はカーソルを次のエントリへ進める
+// accept を満たさないエントリは読み飛ばす
+func (c *mergeCursor) advance() (bool, error) {
+	for {
+		entry, err := c.next()
+		if errors.Is(err, io.EOF) {
+			return false, nil
+		}
+
+		if err != nil {
+			return false, err
+		}
+
+		if c.accept != nil && !c.accept(entry) {
+			continue
+		}
+
+		c.current = entry
+
+		return true, nil
+	}
+}
+
+// isSorted は入力が compare の順にソート済みか確認する
+func isSorted(source Source, compare func(a string, b string) int, maxLineSize int) (bool, error) {
+	file, err := source.Open()
+	if err != nil {
+		return false, err
+	}
+
+	defer file.Close()
+
+	reader := NewReader(file)
+	reader.SetMaxLineSize(maxLineSize)
+
+	var prevKey string
+	first := true
+
+	for {
+		entry, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return true, nil
+		}
+
+		if err != nil {
+			return false, fmt.Errorf("parse error: %s: %w", source.Name, err)
+		}
+
+		if !first && compare(prevKey, entry.Key) > 0 {
+			return false, nil
+		}
+
+		prevKey = entry.Key
+		first = false
+	}
+}
+
+// mergeQueue は mergeCursor の優先度付きキュー (container/heap)
+// キーの順、同じキーであれば sources の順に取り出す
+type mergeQueue struct {
+	cursors []*mergeCursor
+	compare func(a string, b string) int
+}
+
+func (q *mergeQueue) Len() int { return len(q.cursors) }
+
+func (q *mergeQueue) Less(i, j int) bool {
+	if result := q.compare(q.cursors[i].current.Key, q.cursors[j].current.Key); result != 0 {
+		return result < 0
+	}
+
+	return q.cursors[i].order < q.cursors[j].order
+}
+
+func (q *mergeQueue) Swap(i, j int) { q.cursors[i], q.cursors[j] = q.cursors[j], q.cursors[i] }
+
+func (q *mergeQueue) Push(x any) { q.cursors = append(q.cursors, x.(*mergeCursor)) }
+
+func (q *mergeQueue) Pop() any {
+	last := q.cursors[len(q.cursors)-1]
+	q.cursors = q.cursors[:len(q.cursors)-1]
+
+	return last
+}
@@ -0,0 +1,182 @@
+package dictionary
+
+import (
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// stringSource はテスト用の文字列を入力とする Source
+func stringSource(name string, data string) Source {
+	return Source{
+		Name: name,
+		Open: func() (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader(data)), nil
+		},
+	}
+}
+
+func TestMerge(t *testing.T) {
+	sources := []Source{
+		stringSource("a", `{"key": "あい", "value": ["愛"]}`+"\n"+`{"key": "きのう", "value": ["機能"]}`),
+		stringSource("b", `{"key": "きのう", "value": ["昨日", "機能"]}`+"\n"+`{"key": "あい", "value": ["藍"]}`),
+	}
+
+	var keys []string
+	var values [][]string
+
+	for entry, err := range Merge(sources, MergePolicy{}) {
+		if err != nil {
+			t.Fatalf("merge failed: %v", err)
+		}
+
+		keys = append(keys, entry.Key)
+		values = append(values, entry.Value)
+	}
+
+	if !slices.Equal(keys, []string{"あい", "きのう"}) {
+		t.Fatalf("unexpected keys: %v", keys)
+	}
+
+	if !slices.Equal(values[0], []string{"愛", "藍"}) || !slices.Equal(values[1], []string{"機能", "昨日"}) {
+		t.Fatalf("unexpected values: %v", values)
+	}
+}
+
+func TestMerge_Numeric(t *testing.T) {
+	sources := []Source{
+		stringSource("a", `{"key": "#かい", "value": ["#1回"]}`+"\n"+`{"key": "あい", "value": ["愛"]}`),
+		stringSource("b", `{"key": "#かい", "value": ["#3回", "#1回"]}`+"\n"+`{"key": "#がつ", "value": ["#1月"]}`),
+	}
+
+	var keys []string
+	var values [][]string
+
+	for entry, err := range Merge(sources, MergePolicy{}) {
+		if err != nil {
+			t.Fatalf("merge failed: %v", err)
+		}
+
+		keys = append(keys, entry.Key)
+		values = append(values, entry.Value)
+	}
+
+	if !slices.Equal(keys, []string{"#かい", "#がつ", "あい"}) {
+		t.Fatalf("unexpected keys: %v", keys)
+	}
+
+	if !slices.Equal(values[0], []string{"#1回", "#3回"}) {
+		t.Fatalf("unexpected values: %v", values)
+	}
+}
+
+func TestMerge_Collation(t *testing.T) {
+	sources := []Source{
+		stringSource("a", `{"key": "つ", "value": ["津"]}`+"\n"+`{"key": "っ", "value": ["ッ"]}`),
+	}
+
+	var keys []string
+
+	for entry, err := range Merge(sources, MergePolicy{Compare: strings.Compare}) {
+		if err != nil {
+			t.Fatalf("merge failed: %v", err)
+		}
+
+		keys = append(keys, entry.Key)
+	}
+
+	if !slices.Equal(keys, []string{"っ", "つ"}) {
+		t.Fatalf("expected codepoint order, got %v", keys)
+	}
+}
+
+func TestMerge_Tags(t *testing.T) {
+	sources := []Source{
+		stringSource("a", `{"key": "あい", "value": ["愛"], "pos": "noun"}`+"\n"+`{"key": "いにしえ", "value": ["古"], "tags": ["archaic"]}`),
+		stringSource("b", `{"key": "あい", "value": ["藍"], "tags": ["name"]}`+"\n"+`{"key": "いにしえ", "value": ["古え"]}`),
+	}
+
+	var entries []Entry
+
+	for entry, err := range Merge(sources, MergePolicy{ExcludeTags: []string{"archaic"}}) {
+		if err != nil {
+			t.Fatalf("merge failed: %v", err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if len(entries) != 2 || entries[0].Pos != "noun" || !slices.Equal(entries[0].Tags, []string{"name"}) {
+		t.Fatalf("expected pos and tags to be merged, got %v", entries)
+	}
+
+	if !slices.Equal(entries[1].Value, []string{"古え"}) {
+		t.Fatalf("expected archaic entry to be excluded, got %v", entries[1])
+	}
+
+	var keys []string
+
+	for entry, err := range Merge(sources, MergePolicy{IncludeTags: []string{"archaic"}}) {
+		if err != nil {
+			t.Fatalf("merge failed: %v", err)
+		}
+
+		keys = append(keys, entry.Key)
+	}
+
+	if !slices.Equal(keys, []string{"いにしえ"}) {
+		t.Fatalf("expected only archaic entries, got %v", keys)
+	}
+}
+
+func TestMerge_Provenance(t *testing.T) {
+	sources := []Source{
+		stringSource("a", `{"key": "あい", "value": ["愛"], "license": "MIT"}`+"\n"+`{"key": "きのう", "value": ["機能"], "license": "MIT"}`),
+		stringSource("b", `{"key": "あい", "value": ["藍"], "license": "GPL-2.0-or-later"}`+"\n"+`{"key": "きのう", "value": ["昨日"], "license": "MIT"}`),
+	}
+
+	var licenses []string
+
+	for entry, err := range Merge(sources, MergePolicy{}) {
+		if err != nil {
+			t.Fatalf("merge failed: %v", err)
+		}
+
+		licenses = append(licenses, entry.License)
+	}
+
+	if !slices.Equal(licenses, []string{"MIT AND GPL-2.0-or-later", "MIT"}) {
+		t.Fatalf("expected every license to be kept, got %v", licenses)
+	}
+}
+
+func TestMerge_ProvenanceLicenses(t *testing.T) {
+	sources := []Source{
+		stringSource("local", `{"key": "あい", "value": ["愛"], "contributor": "alice", "license": "CC0-1.0"}`),
+		stringSource("skk", `{"key": "あい", "value": ["藍", "愛"], "source": "SKK-JISYO.L", "license": "GPL-2.0-or-later"}`),
+		stringSource("dual", `{"key": "あい", "value": ["哀"], "source": "corpus", "license": "MIT OR Apache-2.0"}`),
+		stringSource("unknown", `{"key": "あい", "value": ["相"]}`),
+	}
+
+	var entries []Entry
+
+	for entry, err := range Merge(sources, MergePolicy{}) {
+		if err != nil {
+			t.Fatalf("merge failed: %v", err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	// 異なるライセンスの入力をまとめても全ての出典とライセンスが残る
+	expected := Provenance{
+		Source:      "SKK-JISYO.L, corpus",
+		Contributor: "alice",
+		License:     "CC0-1.0 AND GPL-2.0-or-later AND (MIT OR Apache-2.0)",
+	}
+
+	if len(entries) != 1 || entries[0].Provenance != expected {
+		t.Fatalf("expected %+v, got %+v", expected, entries)
+	}
+}
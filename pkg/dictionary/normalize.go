@@ -0,0 +1,181 @@
+package dictionary
+
+import (
+	"sort"
+	"strings"
+)
+
+// NormalizeOptions は Normalize の動作を指定する
+type NormalizeOptions struct {
+	// Dedupe は同じキーのエントリを Union と同じ規則で1つにまとめ、重複する値を取り除く
+	// pos は最初に指定されたもの、tags は全ての和集合とする
+	// 出典は項目ごとに異なる値を全て残す (unionProvenance)
+	Dedupe bool
+	// ByWeight は値を weight の降順に並べる (weight の無い値は 0 として扱い、同順位は元の順序を保つ)
+	ByWeight bool
+}
+
+// Sort は compare の順にエントリを安定ソートする
+func Sort(entries []Entry, compare func(a string, b string) int) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return compare(entries[i].Key, entries[j].Key) < 0
+	})
+}
+
+// Normalize はエントリの値を正規化する
+// エントリの順序は保ち、まとめたエントリは最初に現れた位置に残す
+func Normalize(entries []Entry, options NormalizeOptions) []Entry {
+	if options.Dedupe {
+		var merged []Entry
+		indexes := make(map[string]int)
+
+		for _, entry := range entries {
+			index, ok := indexes[entry.Key]
+
+			// 初回のキーは重複する値だけを取り除いて投入
+			if !ok {
+				entry.Value = Union(nil, entry.Value)
+				indexes[entry.Key] = len(merged)
+				merged = append(merged, entry)
+				continue
+			}
+
+			// ここから先は重複キー
+			merged[index].Value = Union(merged[index].Value, entry.Value)
+			merged[index].Weight = unionWeight(merged[index].Weight, entry.Weight)
+			merged[index].Pos = unionPos(merged[index].Pos, entry.Pos)
+			merged[index].Tags = unionTags(merged[index].Tags, entry.Tags)
+			merged[index].Provenance = unionProvenance(merged[index].Provenance, entry.Provenance)
+		}
+
+		entries = merged
+	}
+
+	if options.ByWeight {
+		for _, entry := range entries {
+			if len(entry.Weight) == 0 {
+				continue
+			}
+
+			sort.SliceStable(entry.Value, func(i, j int) bool {
+				return entry.Weight[entry.Value[i]] > entry.Weight[entry.Value[j]]
+			})
+		}
+	}
+
+	return entries
+}
+
+// Union は source に含まれていない input の値だけを末尾に追加する
+func Union(source []string, input []string) []string {
+	set := newCandidateSet(source)
+	set.add(input)
+
+	return set.values
+}
+
+// unionWeight は weight をまとめる、既に存在する値の weight は上書きしない
+func unionWeight(source map[string]int, input map[string]int) map[string]int {
+	for value, weight := range input {
+		if source == nil {
+			source = make(map[string]int)
+		}
+
+		if _, ok := source[value]; ok {
+			continue
+		}
+
+		source[value] = weight
+	}
+
+	return source
+}
+
+// candidateSet は重複を許さない値のリスト、追加順を保持する
+type candidateSet struct {
+	values []string
+	seen   map[string]struct{}
+}
+
+// newCandidateSet は values を初期値とした candidateSet を作成する
+func newCandidateSet(values []string) *candidateSet {
+	set := &candidateSet{values: values, seen: make(map[string]struct{}, len(values))}
+
+	for _, value := range values {
+		set.seen[value] = struct{}{}
+	}
+
+	return set
+}
+
+// add はまだ含まれていない値だけを末尾に追加する
+func (s *candidateSet) add(values []string) {
+	for _, value := range values {
+		if _, ok := s.seen[value]; ok {
+			continue
+		}
+
+		s.seen[value] = struct{}{}
+		s.values = append(s.values, value)
+	}
+}
+
+// unionPos は pos をまとめる、既に指定されている pos は上書きしない
+func unionPos(source string, input string) string {
+	if source != "" {
+		return source
+	}
+
+	return input
+}
+
+// unionTags は tags をまとめる、どちらにも無い時は nil のまま
+func unionTags(source []string, input []string) []string {
+	if len(source) == 0 && len(input) == 0 {
+		return nil
+	}
+
+	return Union(source, input)
+}
+
+// 出典をまとめる時の区切り
+const (
+	provenanceSeparator = ", "
+	licenseSeparator    = " AND " // SPDX の複合ライセンス式
+)
+
+// unionProvenance は出典をまとめる
+// 項目ごとに異なる値を全て残し、出典とライセンスの分からない候補が混ざっても既知の値は消さない
+//   - source, contributor: ", " 区切り (SKK-JISYO.L, local)
+//   - license: SPDX の AND 式 (MIT AND GPL-2.0-or-later)
+//
+// 候補ごとの出典が必要な時はまとめる前のエントリから集計すること
+func unionProvenance(source Provenance, input Provenance) Provenance {
+	return Provenance{
+		Source:      unionField(source.Source, input.Source, provenanceSeparator),
+		Contributor: unionField(source.Contributor, input.Contributor, provenanceSeparator),
+		License:     unionField(spdxTerm(source.License), spdxTerm(input.License), licenseSeparator),
+	}
+}
+
+// unionField は separator 区切りの値の一覧 source に input の値を追加する
+func unionField(source string, input string, separator string) string {
+	split := func(field string) []string {
+		if field == "" {
+			return nil
+		}
+
+		return strings.Split(field, separator)
+	}
+
+	return strings.Join(Union(split(source), split(input)), separator)
+}
+
+// spdxTerm は OR を含むライセンス式を AND で繋げられるよう括弧で囲む
+func spdxTerm(license string) string {
+	if !strings.Contains(license, " OR ") || strings.Contains(license, licenseSeparator) || strings.HasPrefix(license, "(") {
+		return license
+	}
+
+	return "(" + license + ")"
+}
@@ -0,0 +1,49 @@
+package dictionary
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	entries := []Entry{
+		{Key: "きのう", Value: []string{"機能", "昨日", "機能"}},
+		{Key: "きのう", Value: []string{"帰納", "昨日"}, Weight: map[string]int{"帰納": 5, "昨日": 1}},
+		{Key: "あい", Value: []string{"愛"}},
+	}
+
+	normalized := Normalize(entries, NormalizeOptions{Dedupe: true, ByWeight: true})
+
+	if len(normalized) != 2 {
+		t.Fatalf("expected 2 entries, got %d: %v", len(normalized), normalized)
+	}
+
+	expected := []string{"帰納", "昨日", "機能"}
+	if !slices.Equal(normalized[0].Value, expected) {
+		t.Fatalf("expected %v, got %v", expected, normalized[0].Value)
+	}
+
+	if normalized[1].Key != "あい" {
+		t.Fatalf("expected entry order to be kept, got %q", normalized[1].Key)
+	}
+}
+
+func TestUnionProvenance(t *testing.T) {
+	tests := []struct {
+		source   Provenance
+		input    Provenance
+		expected Provenance
+	}{
+		{Provenance{License: "MIT"}, Provenance{License: "MIT"}, Provenance{License: "MIT"}},
+		{Provenance{License: "MIT"}, Provenance{}, Provenance{License: "MIT"}},
+		{Provenance{Source: "a", License: "MIT"}, Provenance{Source: "b", License: "GPL-2.0-or-later"}, Provenance{Source: "a, b", License: "MIT AND GPL-2.0-or-later"}},
+		{Provenance{Source: "a, b", License: "MIT AND GPL-2.0-or-later"}, Provenance{Source: "b", License: "MIT"}, Provenance{Source: "a, b", License: "MIT AND GPL-2.0-or-later"}},
+		{Provenance{License: "MIT"}, Provenance{License: "MIT OR Apache-2.0"}, Provenance{License: "MIT AND (MIT OR Apache-2.0)"}},
+	}
+
+	for _, test := range tests {
+		if got := unionProvenance(test.source, test.input); got != test.expected {
+			t.Errorf("unionProvenance(%+v, %+v) = %+v, want %+v", test.source, test.input, got, test.expected)
+		}
+	}
+}
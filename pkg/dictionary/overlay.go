@@ -0,0 +1,81 @@
+package dictionary
+
+import (
+	"iter"
+	"maps"
+	"slices"
+)
+
+// Overlay は base の上に利用者の辞書 user を重ねたエントリを compare の順に返す
+//   - 同じキーは user の候補をその順序 (学習した順序) のまま先頭に置き、base の候補を後ろに続ける
+//   - user にしか無いキーもそのまま追加する
+//   - base は compare の順にソート済み (Merge の結果など) であること、キーの無いエントリは末尾とする
+func Overlay(base iter.Seq2[Entry, error], user []Entry, compare func(a string, b string) int) iter.Seq2[Entry, error] {
+	if compare == nil {
+		compare = Compare
+	}
+
+	// 利用者の辞書は小さいためメモリ上で整える
+	user = Normalize(slices.Clone(user), NormalizeOptions{Dedupe: true})
+	Sort(user, compare)
+
+	compareKey := func(a string, b string) int {
+		switch {
+		case a == "" && b == "":
+			return 0
+		case a == "":
+			return 1
+		case b == "":
+			return -1
+		}
+
+		return compare(a, b)
+	}
+
+	return func(yield func(Entry, error) bool) {
+		index := 0
+
+		for entry, err := range base {
+			if err != nil {
+				yield(Entry{}, err)
+				return
+			}
+
+			// base より前に来る利用者のキー
+			for index < len(user) && compareKey(user[index].Key, entry.Key) < 0 {
+				if !yield(user[index], nil) {
+					return
+				}
+
+				index++
+			}
+
+			if index < len(user) && user[index].Key == entry.Key {
+				entry = overlayEntry(user[index], entry)
+				index++
+			}
+
+			if !yield(entry, nil) {
+				return
+			}
+		}
+
+		for ; index < len(user); index++ {
+			if !yield(user[index], nil) {
+				return
+			}
+		}
+	}
+}
+
+// overlayEntry は同じキーの base のエントリに user のエントリを重ねる
+func overlayEntry(user Entry, base Entry) Entry {
+	return Entry{
+		Key:        user.Key,
+		Value:      Union(slices.Clone(user.Value), base.Value),
+		Weight:     unionWeight(maps.Clone(user.Weight), base.Weight),
+		Pos:        unionPos(user.Pos, base.Pos),
+		Tags:       unionTags(user.Tags, base.Tags),
+		Provenance: unionProvenance(user.Provenance, base.Provenance),
+	}
+}
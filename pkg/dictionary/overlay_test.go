@@ -0,0 +1,42 @@
+package dictionary
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestOverlay(t *testing.T) {
+	sources := []Source{
+		stringSource("a", `{"key": "かい", "value": ["回", "階", "会"]}`+"\n"+`{"key": "きのう", "value": ["機能"]}`),
+	}
+
+	user := []Entry{
+		{Key: "きのう", Value: []string{"昨日"}},
+		{Key: "かい", Value: []string{"会", "貝"}},
+		{Key: "あい", Value: []string{"藍"}},
+	}
+
+	var keys []string
+	var values [][]string
+
+	for entry, err := range Overlay(Merge(sources, MergePolicy{}), user, nil) {
+		if err != nil {
+			t.Fatalf("overlay failed: %v", err)
+		}
+
+		keys = append(keys, entry.Key)
+		values = append(values, entry.Value)
+	}
+
+	if !slices.Equal(keys, []string{"あい", "かい", "きのう"}) {
+		t.Fatalf("unexpected keys: %v", keys)
+	}
+
+	expected := [][]string{{"藍"}, {"会", "貝", "回", "階"}, {"昨日", "機能"}}
+
+	for i := range expected {
+		if !slices.Equal(values[i], expected[i]) {
+			t.Fatalf("expected user candidates first, got %v", values)
+		}
+	}
+}
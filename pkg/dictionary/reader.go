@@ -0,0 +1,96 @@
+package dictionary
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"siguma0013/reskk-dictionary/internal/utility"
+)
+
+// ErrLineTooLong は SetMaxLineSize を超える行を読み込んだ時のエラー
+var ErrLineTooLong = utility.ErrLineTooLong
+
+// ParseError はパースに失敗した行番号を保持するエラー
+type ParseError struct {
+	Line int
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Reader は JSONL 形式の辞書を1エントリずつ読み込む
+//   - 行の長さに上限は無い (SetMaxLineSize で設定できる)
+//   - 空行は読み飛ばす
+type Reader struct {
+	lines *utility.LineReader
+}
+
+// NewReader は reader から辞書を読み込む Reader を作成する
+func NewReader(reader io.Reader) *Reader {
+	return &Reader{lines: utility.NewLineReader(reader)}
+}
+
+// SetMaxLineSize は1行の最大バイト数を設定する、0 は無制限
+func (r *Reader) SetMaxLineSize(size int) {
+	r.lines.MaxSize = size
+}
+
+// Read は次のエントリを返す、読み込むエントリが無い時は io.EOF を返す
+//   - パースに失敗した時は *ParseError を返し、続けて次の行を読み込める
+//   - 長すぎる行や IO エラーの時はそれ以降の読み込みはできない
+func (r *Reader) Read() (Entry, error) {
+	var entry Entry
+
+	for r.lines.Scan() {
+		line := r.lines.Bytes()
+
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return Entry{}, &ParseError{Line: r.lines.Line(), Err: err}
+		}
+
+		return entry, nil
+	}
+
+	if err := r.lines.Err(); err != nil {
+		return Entry{}, err
+	}
+
+	return Entry{}, io.EOF
+}
+
+// Line は直前に読み込んだエントリの行番号 (1始まり) を返す
+func (r *Reader) Line() int {
+	return r.lines.Line()
+}
+
+// ReadAll は reader の全エントリを読み込む
+func ReadAll(reader io.Reader) ([]Entry, error) {
+	entryReader := NewReader(reader)
+
+	var entries []Entry
+
+	for {
+		entry, err := entryReader.Read()
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+}
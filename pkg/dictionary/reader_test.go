@@ -0,0 +1,125 @@
+package dictionary
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestReaderWriter_RoundTrip(t *testing.T) {
+	entries := []Entry{
+		{Key: "あい", Value: []string{"愛", "藍"}},
+		{Key: "きのう", Value: []string{"機能"}, Weight: map[string]int{"機能": 1}},
+	}
+
+	var buffer bytes.Buffer
+
+	writer := NewWriter(&buffer)
+	for _, entry := range entries {
+		if err := writer.Write(entry); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+
+	expected := `{"key": "あい", "value": ["愛", "藍"]}` + "\n" +
+		`{"key": "きのう", "value": ["機能"], "weight": {"機能": 1}}` + "\n"
+	if buffer.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, buffer.String())
+	}
+
+	read, err := ReadAll(&buffer)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+
+	if len(read) != 2 || read[1].Key != "きのう" || read[1].Weight["機能"] != 1 {
+		t.Fatalf("unexpected entries: %v", read)
+	}
+}
+
+func TestFormat_Numeric(t *testing.T) {
+	line, err := Format(Entry{Key: "#かい", Value: []string{"#1回", "#3回"}})
+	if err != nil {
+		t.Fatalf("format failed: %v", err)
+	}
+
+	if line != `{"key": "#かい", "value": ["#1回", "#3回"]}` {
+		t.Fatalf("unexpected line: %s", line)
+	}
+}
+
+func TestReader_ParseError(t *testing.T) {
+	reader := NewReader(strings.NewReader("{\"key\": \"あ\", \"value\": [\"亜\"]}\n\nnot json\n{\"key\": \"い\", \"value\": [\"胃\"]}\n"))
+
+	if _, err := reader.Read(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var parseError *ParseError
+	if _, err := reader.Read(); !errors.As(err, &parseError) || parseError.Line != 3 {
+		t.Fatalf("expected parse error on line 3, got %v", err)
+	}
+
+	// パースエラーの後も読み込みを続けられる
+	if entry, err := reader.Read(); err != nil || entry.Key != "い" {
+		t.Fatalf("expected next entry after parse error, got %v %v", entry, err)
+	}
+
+	if _, err := reader.Read(); !errors.Is(err, io.EOF) {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestReader_LineTooLong(t *testing.T) {
+	reader := NewReader(strings.NewReader("{\"key\": \"あ\", \"value\": [\"亜\"]}\n{\"key\": \"いい\", \"value\": [\"胃胃胃胃胃胃胃胃\"]}\n{\"key\": \"う\", \"value\": [\"鵜\"]}\n"))
+	reader.SetMaxLineSize(40)
+
+	if _, err := reader.Read(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 長すぎる行は ParseError ではないため、読み飛ばして続けることはできない
+	for range 2 {
+		var parseError *ParseError
+
+		_, err := reader.Read()
+		if !errors.Is(err, ErrLineTooLong) || errors.As(err, &parseError) {
+			t.Fatalf("expected line too long error, got %v", err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		entry    Entry
+		expected error
+	}{
+		{Entry{Key: "あ", Value: []string{"亜"}}, nil},
+		{Entry{Value: []string{"亜"}}, ErrEmptyKey},
+		{Entry{Key: "あ"}, ErrEmptyValue},
+		{Entry{Key: "あ", Value: []string{"亜", ""}}, ErrEmptyCandidate},
+		{Entry{Key: "#かい", Value: []string{"#1回", "#3回"}}, nil},
+		{Entry{Key: "しーしゃーぷ", Value: []string{"C#"}}, nil},
+		{Entry{Key: "#かい", Value: []string{"#回"}}, ErrInvalidNumeric},
+		{Entry{Key: "#かい", Value: []string{"#6回"}}, ErrUnknownNumeric},
+		{Entry{Key: "#がつ#にち", Value: []string{"#1月#7日"}}, ErrUnknownNumeric},
+		{Entry{Key: "#かい", Value: []string{"回"}}, ErrNumericMismatch},
+		{Entry{Key: "#がつ#にち", Value: []string{"#1月#1日", "#3月"}}, ErrNumericMismatch},
+		{Entry{Key: "かい", Value: []string{"#1回"}}, ErrNumericMismatch},
+		{Entry{Key: "あい", Value: []string{"愛"}, Pos: "noun", Tags: []string{"name"}}, nil},
+		{Entry{Key: "あい", Value: []string{"愛"}, Pos: "verb"}, ErrUnknownPos},
+		{Entry{Key: "あい", Value: []string{"愛"}, Tags: []string{"tech", "love"}}, ErrUnknownTag},
+	}
+
+	for _, test := range tests {
+		if err := Validate(test.entry); !errors.Is(err, test.expected) {
+			t.Fatalf("%v: expected %v, got %v", test.entry, test.expected, err)
+		}
+	}
+}
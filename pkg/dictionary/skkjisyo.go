@@ -0,0 +1,123 @@
+package dictionary
+
+import (
+	"errors"
+	"io"
+	"siguma0013/reskk-dictionary/internal/utility"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidSkkLine は SKK-JISYO の行が "読み /候補/.../" の形式でない時のエラー
+	ErrInvalidSkkLine = errors.New("invalid SKK-JISYO line")
+)
+
+// SKK-JISYO の送りあり・送りなしエントリの開始を示すコメント
+const (
+	skkOkuriAriMarker  = ";; okuri-ari entries."
+	skkOkuriNasiMarker = ";; okuri-nasi entries."
+)
+
+// ReadSkkJisyo は SKK-JISYO 形式 (UTF-8 に変換済み) の辞書から送りなしエントリを読み込む
+//   - ; で始まる行はコメントとして読み飛ばす
+//   - 送りありエントリ (";; okuri-ari entries." から ";; okuri-nasi entries." まで) は読み飛ばし、その数を skipped で返す
+//   - 候補の注釈 (; 以降) は取り除き、(concat "...") は展開する
+func ReadSkkJisyo(reader io.Reader) (entries []Entry, skipped int, err error) {
+	lines := utility.NewLineReader(reader)
+
+	okuriAri := false
+
+	for lines.Scan() {
+		line := lines.Text()
+
+		switch {
+		case strings.HasPrefix(line, skkOkuriAriMarker):
+			okuriAri = true
+			continue
+		case strings.HasPrefix(line, skkOkuriNasiMarker):
+			okuriAri = false
+			continue
+		case line == "" || strings.HasPrefix(line, ";"):
+			continue
+		case okuriAri:
+			skipped++
+			continue
+		}
+
+		entry, err := parseSkkLine(line)
+		if err != nil {
+			return nil, 0, &ParseError{Line: lines.Line(), Err: err}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err := lines.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return entries, skipped, nil
+}
+
+// parseSkkLine は SKK-JISYO の1行をエントリにする
+func parseSkkLine(line string) (Entry, error) {
+	key, candidates, ok := strings.Cut(line, " /")
+	if !ok || key == "" || !strings.HasSuffix(candidates, "/") {
+		return Entry{}, ErrInvalidSkkLine
+	}
+
+	var values []string
+
+	for _, candidate := range strings.Split(strings.TrimSuffix(candidates, "/"), "/") {
+		candidate, _, _ = strings.Cut(candidate, ";")
+
+		if candidate == "" {
+			continue
+		}
+
+		values = append(values, UnescapeSkkCandidate(candidate))
+	}
+
+	if len(values) == 0 {
+		return Entry{}, ErrInvalidSkkLine
+	}
+
+	return Entry{Key: key, Value: values}, nil
+}
+
+// UnescapeSkkCandidate は (concat "...") 形式の候補を展開する
+// 8進数のエスケープ (\057 など) と \\ \" に対応し、それ以外の候補はそのまま返す
+func UnescapeSkkCandidate(candidate string) string {
+	inner, ok := strings.CutPrefix(candidate, `(concat "`)
+	if !ok {
+		return candidate
+	}
+
+	inner, ok = strings.CutSuffix(inner, `")`)
+	if !ok {
+		return candidate
+	}
+
+	var builder strings.Builder
+
+	for i := 0; i < len(inner); i++ {
+		if inner[i] != '\\' || i+1 >= len(inner) {
+			builder.WriteByte(inner[i])
+			continue
+		}
+
+		if i+3 < len(inner) {
+			if code, err := strconv.ParseUint(inner[i+1:i+4], 8, 8); err == nil {
+				builder.WriteByte(byte(code))
+				i += 3
+				continue
+			}
+		}
+
+		builder.WriteByte(inner[i+1])
+		i++
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,42 @@
+package dictionary
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestReadSkkJisyo(t *testing.T) {
+	entries, skipped, err := ReadSkkJisyo(strings.NewReader(strings.Join([]string{
+		";; -*- coding: utf-8 -*-",
+		";; okuri-ari entries.",
+		"かえr /帰/返/",
+		"みr /見/",
+		";; okuri-nasi entries.",
+		"きのう /昨日;yesterday/機能/",
+		`すらっしゅ /(concat "\057")/`,
+	}, "\n")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(entries) != 2 || skipped != 2 {
+		t.Fatalf("expected 2 okuri-ari entries to be skipped, got %v (%d skipped)", entries, skipped)
+	}
+
+	if entries[0].Key != "きのう" || !slices.Equal(entries[0].Value, []string{"昨日", "機能"}) {
+		t.Fatalf("expected annotations to be removed, got %v", entries[0])
+	}
+
+	if !slices.Equal(entries[1].Value, []string{"/"}) {
+		t.Fatalf("expected concat to be expanded, got %v", entries[1])
+	}
+
+	_, _, err = ReadSkkJisyo(strings.NewReader("きのう 昨日\n"))
+
+	var parseError *ParseError
+	if !errors.As(err, &parseError) || parseError.Line != 1 || !errors.Is(err, ErrInvalidSkkLine) {
+		t.Fatalf("expected parse error on line 1, got %v", err)
+	}
+}
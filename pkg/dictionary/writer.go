@@ -0,0 +1,83 @@
+package dictionary
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// Writer はエントリを辞書ファイルの正規フォーマットで書き出す
+type Writer struct {
+	writer *bufio.Writer
+}
+
+// NewWriter は writer へ書き出す Writer を作成する
+// 書き出し終了時に Flush を呼び出すこと
+func NewWriter(writer io.Writer) *Writer {
+	return &Writer{writer: bufio.NewWriter(writer)}
+}
+
+// Write はエントリを1行書き出す
+func (w *Writer) Write(entry Entry) error {
+	line, err := Format(entry)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.writer.WriteString(line); err != nil {
+		return err
+	}
+
+	return w.writer.WriteByte('\n')
+}
+
+// Flush はバッファに残ったデータを書き出す
+func (w *Writer) Flush() error {
+	return w.writer.Flush()
+}
+
+// Format はエントリを辞書ファイルの正規フォーマットの1行に変換する
+// コロンとカンマの後ろに1つだけスペースを入れ、文字列中の記号はそのまま残す
+func Format(entry Entry) (string, error) {
+	var buffer bytes.Buffer
+
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(entry); err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+
+	inString := false
+	escaped := false
+
+	for _, b := range bytes.TrimRight(buffer.Bytes(), "\n") {
+		builder.WriteByte(b)
+
+		// 文字列中はエスケープだけを追跡する
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case b == '\\':
+				escaped = true
+			case b == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch b {
+		case '"':
+			inString = true
+		case ':', ',':
+			builder.WriteByte(' ')
+		}
+	}
+
+	return builder.String(), nil
+}
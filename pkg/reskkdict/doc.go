@@ -0,0 +1,13 @@
+// Package reskkdict は build-index で作成した辞書インデックスを読み込み、JSONL をパースせずに検索する
+//
+// インデックスはキーのバイト順にソートしたレコード列とオフセット表からなるバイナリ形式で、
+// mmap してそのまま二分探索できる
+//
+//	magic   [8]byte   "RESKKIDX"
+//	version uint32    (little endian)
+//	count   uint32    レコード数 N
+//	offsets [N+1]uint32 データ領域先頭からの各レコードの開始位置 (末尾は終端)
+//	data    レコード列
+//
+// レコードは uvarint の長さを前置したキー、uvarint の候補数、同じく長さを前置した候補の並び
+package reskkdict
@@ -0,0 +1,213 @@
+package reskkdict
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+const (
+	// Magic はインデックスファイル先頭の識別子
+	Magic = "RESKKIDX"
+	// Version はインデックス形式のバージョン
+	Version = 1
+
+	headerSize = len(Magic) + 4 + 4
+)
+
+// ErrInvalidIndex はインデックスの形式が不正な時のエラー
+var ErrInvalidIndex = errors.New("invalid index")
+
+// Entry はインデックスに格納される1エントリ
+type Entry struct {
+	Key        string
+	Candidates []string
+}
+
+// Index は読み込み済みのインデックス
+type Index struct {
+	data    []byte
+	count   int
+	offsets []byte
+	records []byte
+	release func() error
+}
+
+// New はメモリ上のバイト列からインデックスを作成する
+// data はインデックスを利用している間は変更しないこと
+func New(data []byte) (*Index, error) {
+	if len(data) < headerSize || string(data[:len(Magic)]) != Magic {
+		return nil, fmt.Errorf("%w: bad magic", ErrInvalidIndex)
+	}
+
+	if version := binary.LittleEndian.Uint32(data[len(Magic):]); version != Version {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidIndex, version)
+	}
+
+	count := int(binary.LittleEndian.Uint32(data[len(Magic)+4:]))
+	offsetsEnd := headerSize + (count+1)*4
+
+	if offsetsEnd > len(data) {
+		return nil, fmt.Errorf("%w: truncated offset table", ErrInvalidIndex)
+	}
+
+	index := &Index{
+		data:    data,
+		count:   count,
+		offsets: data[headerSize:offsetsEnd],
+		records: data[offsetsEnd:],
+	}
+
+	if int(index.offset(count)) != len(index.records) {
+		return nil, fmt.Errorf("%w: truncated records", ErrInvalidIndex)
+	}
+
+	// 壊れたオフセット表で範囲外アクセスしないよう単調増加を確認する
+	for i := range count {
+		if index.offset(i) > index.offset(i+1) {
+			return nil, fmt.Errorf("%w: broken offset table", ErrInvalidIndex)
+		}
+	}
+
+	// 壊れたレコードで Entry がパニックや巨大な確保をしないよう全レコードを確認する
+	for i := range count {
+		if _, err := parseRecord(index.record(i)); err != nil {
+			return nil, fmt.Errorf("%w: record %d: %v", ErrInvalidIndex, i, err)
+		}
+	}
+
+	return index, nil
+}
+
+// Close はインデックスが保持するメモリマップを解放する
+func (x *Index) Close() error {
+	if x.release == nil {
+		return nil
+	}
+
+	release := x.release
+	x.release = nil
+
+	return release()
+}
+
+// Len はエントリ数を返す
+func (x *Index) Len() int {
+	return x.count
+}
+
+// Lookup はキーに完全一致するエントリの候補を返す
+func (x *Index) Lookup(key string) ([]string, bool) {
+	i := x.search([]byte(key))
+
+	if i >= x.count || !bytes.Equal(x.key(i), []byte(key)) {
+		return nil, false
+	}
+
+	return x.Entry(i).Candidates, true
+}
+
+// Prefix は prefix から始まるエントリをキーのバイト順に返す
+// limit が 0 以下の時は全件を返す
+func (x *Index) Prefix(prefix string, limit int) []Entry {
+	var entries []Entry
+
+	for i := x.search([]byte(prefix)); i < x.count; i++ {
+		if !bytes.HasPrefix(x.key(i), []byte(prefix)) {
+			break
+		}
+
+		if limit > 0 && len(entries) >= limit {
+			break
+		}
+
+		entries = append(entries, x.Entry(i))
+	}
+
+	return entries
+}
+
+// Entry は i 番目のエントリを返す
+func (x *Index) Entry(i int) Entry {
+	// レコードは New で検証済み
+	entry, _ := parseRecord(x.record(i))
+	return entry
+}
+
+// parseRecord はレコードのバイト列をエントリにする
+// varint と長さはレコードの範囲内にあることを確認する
+func parseRecord(record []byte) (Entry, error) {
+	key, record, ok := readBytes(record)
+	if !ok {
+		return Entry{}, errors.New("broken key")
+	}
+
+	count, n := binary.Uvarint(record)
+	if n <= 0 {
+		return Entry{}, errors.New("broken candidate count")
+	}
+
+	record = record[n:]
+
+	// 候補は最低でも長さの1バイトを持つ
+	if count > uint64(len(record)) {
+		return Entry{}, fmt.Errorf("candidate count %d exceeds the record", count)
+	}
+
+	entry := Entry{Key: string(key), Candidates: make([]string, 0, count)}
+
+	for range count {
+		var candidate []byte
+
+		candidate, record, ok = readBytes(record)
+		if !ok {
+			return Entry{}, errors.New("broken candidate")
+		}
+
+		entry.Candidates = append(entry.Candidates, string(candidate))
+	}
+
+	if len(record) != 0 {
+		return Entry{}, errors.New("trailing bytes")
+	}
+
+	return entry, nil
+}
+
+// search は key 以上となる最初のエントリの位置を返す
+func (x *Index) search(key []byte) int {
+	return sort.Search(x.count, func(i int) bool {
+		return bytes.Compare(x.key(i), key) >= 0
+	})
+}
+
+// key は i 番目のエントリのキーを返す
+func (x *Index) key(i int) []byte {
+	key, _, _ := readBytes(x.record(i))
+	return key
+}
+
+// record は i 番目のレコードのバイト列を返す
+func (x *Index) record(i int) []byte {
+	return x.records[x.offset(i):x.offset(i+1)]
+}
+
+// offset は i 番目のレコードの開始位置を返す
+func (x *Index) offset(i int) uint32 {
+	return binary.LittleEndian.Uint32(x.offsets[i*4:])
+}
+
+// readBytes は長さを前置したバイト列を読み込み、残りを返す
+// 長さが不正な時や buffer が足りない時 ok は false
+func readBytes(buffer []byte) (value []byte, rest []byte, ok bool) {
+	length, n := binary.Uvarint(buffer)
+	if n <= 0 || uint64(len(buffer)-n) < length {
+		return nil, nil, false
+	}
+
+	end := n + int(length)
+
+	return buffer[n:end], buffer[end:], true
+}
@@ -0,0 +1,104 @@
+package reskkdict
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestIndex_RoundTrip(t *testing.T) {
+	entries := []Entry{
+		{Key: "きのう", Candidates: []string{"機能", "昨日"}},
+		{Key: "あい", Candidates: []string{"愛"}},
+		{Key: "きの", Candidates: []string{"茸"}},
+		{Key: "きのこ", Candidates: []string{"茸", "キノコ"}},
+		{Key: "さくじょ", Candidates: []string{"削除"}},
+	}
+
+	var buffer bytes.Buffer
+	if err := Write(&buffer, entries); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "test.idx")
+	if err := os.WriteFile(path, buffer.Bytes(), 0o644); err != nil {
+		t.Fatalf("write index: %v", err)
+	}
+
+	index, err := Open(path)
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+
+	defer index.Close()
+
+	if index.Len() != len(entries) {
+		t.Fatalf("expected %d entries, got %d", len(entries), index.Len())
+	}
+
+	for _, entry := range entries {
+		candidates, ok := index.Lookup(entry.Key)
+		if !ok || !slices.Equal(candidates, entry.Candidates) {
+			t.Fatalf("lookup %q: expected %v, got %v (%v)", entry.Key, entry.Candidates, candidates, ok)
+		}
+	}
+
+	if _, ok := index.Lookup("き"); ok {
+		t.Fatalf("expected no exact match for prefix only key")
+	}
+
+	var keys []string
+	for _, entry := range index.Prefix("きの", 0) {
+		keys = append(keys, entry.Key)
+	}
+
+	if expected := []string{"きの", "きのう", "きのこ"}; !slices.Equal(keys, expected) {
+		t.Fatalf("prefix: expected %v, got %v", expected, keys)
+	}
+
+	if limited := index.Prefix("きの", 2); len(limited) != 2 {
+		t.Fatalf("expected prefix limit to be applied, got %d", len(limited))
+	}
+}
+
+func TestWrite_DuplicateKey(t *testing.T) {
+	err := Write(&bytes.Buffer{}, []Entry{{Key: "あ"}, {Key: "あ"}})
+	if err == nil {
+		t.Fatalf("expected duplicate key error")
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := Write(&buffer, []Entry{{Key: "あ", Candidates: []string{"亜"}}}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	data := buffer.Bytes()
+
+	// レコードは "あ" の長さ, "あ", 候補数, "亜" の長さ, "亜" の順
+	records := headerSize + 2*4
+	patch := func(offset int, values ...byte) []byte {
+		broken := slices.Clone(data)
+		copy(broken[records+offset:], values)
+		return broken
+	}
+
+	for name, broken := range map[string][]byte{
+		"empty":            nil,
+		"magic":            append([]byte("XXXXXXXX"), data[8:]...),
+		"truncated":        data[:len(data)-1],
+		"key length":       patch(0, 20),
+		"varint":           patch(0, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff),
+		"candidate count":  patch(4, 0xff),
+		"candidate length": patch(5, 9),
+		"trailing bytes":   patch(4, 0),
+	} {
+		if _, err := New(broken); !errors.Is(err, ErrInvalidIndex) {
+			t.Fatalf("%s: expected ErrInvalidIndex, got %v", name, err)
+		}
+	}
+}
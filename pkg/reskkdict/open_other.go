@@ -0,0 +1,16 @@
+//go:build !unix
+
+package reskkdict
+
+import "os"
+
+// Open はインデックスファイルを読み込んで開く
+// mmap が利用できない環境ではファイル全体をメモリに読み込む
+func Open(path string) (*Index, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(data)
+}
@@ -0,0 +1,46 @@
+//go:build unix
+
+package reskkdict
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// Open はインデックスファイルを mmap して開く
+// 利用後は Close を呼び出すこと
+func Open(path string) (*Index, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Size() < int64(headerSize) {
+		return nil, fmt.Errorf("%w: %s is too small", ErrInvalidIndex, path)
+	}
+
+	data, err := syscall.Mmap(int(file.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, fmt.Errorf("mmap %s: %w", path, err)
+	}
+
+	index, err := New(data)
+	if err != nil {
+		syscall.Munmap(data)
+		return nil, err
+	}
+
+	index.release = func() error {
+		return syscall.Munmap(data)
+	}
+
+	return index, nil
+}
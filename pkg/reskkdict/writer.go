@@ -0,0 +1,61 @@
+package reskkdict
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+)
+
+// Write はエントリをインデックス形式で書き出す
+// エントリはキーのバイト順に並べ替えてから書き出すため、入力の順序は問わない
+func Write(writer io.Writer, entries []Entry) error {
+	sorted := slices.Clone(entries)
+	slices.SortStableFunc(sorted, func(a Entry, b Entry) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	// レコード列とオフセット表を作成
+	var records []byte
+	offsets := make([]uint32, 0, len(sorted)+1)
+
+	for i, entry := range sorted {
+		if i > 0 && sorted[i-1].Key == entry.Key {
+			return fmt.Errorf("duplicate key %q", entry.Key)
+		}
+
+		offsets = append(offsets, uint32(len(records)))
+
+		records = appendBytes(records, entry.Key)
+		records = binary.AppendUvarint(records, uint64(len(entry.Candidates)))
+
+		for _, candidate := range entry.Candidates {
+			records = appendBytes(records, candidate)
+		}
+
+		if len(records) > math.MaxUint32 {
+			return fmt.Errorf("index too large")
+		}
+	}
+
+	offsets = append(offsets, uint32(len(records)))
+
+	buffered := bufio.NewWriter(writer)
+
+	buffered.WriteString(Magic)
+	binary.Write(buffered, binary.LittleEndian, uint32(Version))
+	binary.Write(buffered, binary.LittleEndian, uint32(len(sorted)))
+	binary.Write(buffered, binary.LittleEndian, offsets)
+	buffered.Write(records)
+
+	return buffered.Flush()
+}
+
+// appendBytes は長さを前置した文字列を追加する
+func appendBytes(buffer []byte, value string) []byte {
+	buffer = binary.AppendUvarint(buffer, uint64(len(value)))
+	return append(buffer, value...)
+}